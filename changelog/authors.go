@@ -0,0 +1,146 @@
+package changelog
+
+import (
+	"regexp"
+	"strings"
+)
+
+const handlePattern = `@[\w-]+(?:\.[\w-]+)*`
+
+var handleRegex = regexp.MustCompile(`^` + handlePattern + `$`)
+var byAuthorsRegex = regexp.MustCompile(`\s+by\s+(` + handlePattern + `(?:(?:,\s*|,?\s+and\s+)` + handlePattern + `)*)\.?\s*$`)
+var groupAuthorsRegex = regexp.MustCompile(`\s*\((` + handlePattern + `(?:,\s*` + handlePattern + `)*)\)\.?\s*$`)
+var authorHandleRegex = regexp.MustCompile(handlePattern)
+
+// IsHandle returns whether the given author is a handle, such as "@octocat".
+func IsHandle(author string) bool {
+	return handleRegex.MatchString(author)
+}
+
+// parseAuthors returns the handles of the authors that the description is
+// attributed to, such as "@octocat" for both "Fixed a bug by @octocat" and
+// "Fixed a bug (@octocat)". The attribution may be followed by the group of
+// references of the entry. It returns nil if the description has no
+// attribution.
+func parseAuthors(description string) []string {
+	start, end := findAuthors(description)
+	if start < 0 {
+		return nil
+	}
+
+	return authorHandleRegex.FindAllString(description[start:end], -1)
+}
+
+// findAuthors returns the start and end of the list of authors in the
+// attribution of the description, or -1 if the description has none.
+func findAuthors(description string) (int, int) {
+	if match := findAttribution(description); match != nil {
+		return match[2], match[3]
+	}
+
+	return -1, -1
+}
+
+// findAttribution returns the indexes of the attribution of the description
+// and of its list of authors, as returned by FindStringSubmatchIndex, or nil
+// if the description has none.
+func findAttribution(description string) []int {
+	text := description
+	if parseReferenceGroup(description) != nil {
+		text = description[:referenceGroupRegex.FindStringIndex(description)[0]]
+	}
+
+	for _, regex := range []*regexp.Regexp{byAuthorsRegex, groupAuthorsRegex} {
+		if match := regex.FindStringSubmatchIndex(text); match != nil {
+			return match
+		}
+	}
+
+	return nil
+}
+
+// trimAuthors returns the description without the attribution that ends it.
+func trimAuthors(description string) string {
+	for _, regex := range []*regexp.Regexp{byAuthorsRegex, groupAuthorsRegex} {
+		if index := regex.FindStringIndex(description); index != nil {
+			return description[:index[0]]
+		}
+	}
+
+	return description
+}
+
+// AddAuthor attributes the entry to the given author. A handle, such as
+// "@octocat", is added to the attribution of its description, or attributed
+// with "by @octocat" before its group of references and final period. An
+// author the entry is already attributed to is not added again.
+//
+// An author that is not a handle, such as "Jane Doe", is attributed with "by
+// Jane Doe" before the attribution, group of references and final period of
+// the description. Since only handles are recognised when parsing, it is not
+// one of the authors of the entry.
+func (e *Entry) AddAuthor(author string) {
+	for _, existing := range append(parseAuthors(e.Description), e.Authors...) {
+		if strings.EqualFold(existing, author) {
+			return
+		}
+	}
+
+	if !IsHandle(author) {
+		if strings.Contains(strings.ToLower(e.Description), " by "+strings.ToLower(author)) {
+			return
+		}
+		index := attributionIndex(e.Description)
+		if match := findAttribution(e.Description); match != nil {
+			index = match[0]
+		}
+		e.Description = e.Description[:index] + " by " + author + e.Description[index:]
+		return
+	}
+
+	if _, end := findAuthors(e.Description); end >= 0 {
+		e.Description = e.Description[:end] + ", " + author + e.Description[end:]
+	} else {
+		index := attributionIndex(e.Description)
+		e.Description = e.Description[:index] + " by " + author + e.Description[index:]
+	}
+	e.Authors = append(e.Authors, author)
+}
+
+// attributionIndex returns the index in the description to add a new
+// attribution at, which is before its group of references and final period.
+func attributionIndex(description string) int {
+	text := description
+	if parseReferenceGroup(description) != nil {
+		text = description[:referenceGroupRegex.FindStringIndex(description)[0]]
+	}
+
+	return len(strings.TrimSuffix(strings.TrimRight(text, " "), "."))
+}
+
+// Contributors returns the handles of the authors of the entries of the
+// release, including nested entries, in the order they appear. Every author is
+// returned once.
+func (r Release) Contributors() []string {
+	result := []string{}
+	seen := map[string]bool{}
+
+	var collect func(entries []Entry)
+	collect = func(entries []Entry) {
+		for _, entry := range entries {
+			for _, author := range entry.Authors {
+				if seen[strings.ToLower(author)] {
+					continue
+				}
+				seen[strings.ToLower(author)] = true
+				result = append(result, author)
+			}
+			collect(entry.Children)
+		}
+	}
+	for _, section := range r.Sections {
+		collect(section.Entries)
+	}
+
+	return result
+}
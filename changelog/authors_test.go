@@ -0,0 +1,140 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAuthors(t *testing.T) {
+	testCases := []struct {
+		description     string
+		expectedAuthors []string
+	}{
+		{"Fixed a bug by @octocat", []string{"@octocat"}},
+		{"Fixed a bug by @octocat.", []string{"@octocat"}},
+		{"Fixed a bug (@octocat)", []string{"@octocat"}},
+		{"Fixed a bug by @octocat, @hubot and @jane.doe", []string{"@octocat", "@hubot", "@jane.doe"}},
+		{"Fixed a bug (@octocat, @hubot).", []string{"@octocat", "@hubot"}},
+		{"Fixed a bug by @octocat (#12)", []string{"@octocat"}},
+		{"Fixed a bug (#12) by @octocat", []string{"@octocat"}},
+		{"Fixed a bug caused by @media queries in the page", nil},
+		{"Fixed a bug by octocat", nil},
+		{"Fixed a bug", nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			result := parseAuthors(testCase.description)
+
+			if !reflect.DeepEqual(result, testCase.expectedAuthors) {
+				t.Errorf("expected authors to be %v, but was %v", testCase.expectedAuthors, result)
+			}
+		})
+	}
+}
+
+func TestParseReferences_WhenFollowedByAuthors_ReturnsReferences(t *testing.T) {
+	result := parseReferences("Fixed a bug (#12) by @octocat")
+
+	expectedReferences := []Reference{{Kind: IssueReference, ID: "12"}}
+	if !reflect.DeepEqual(result, expectedReferences) {
+		t.Errorf("expected references to be %v, but was %v", expectedReferences, result)
+	}
+}
+
+func TestAddAuthor(t *testing.T) {
+	testCases := []struct {
+		name                string
+		description         string
+		expectedDescription string
+	}{
+		{"first", "A bug.", "A bug by @octocat."},
+		{"another", "A bug by @hubot.", "A bug by @hubot, @octocat."},
+		{"group", "A bug (@hubot)", "A bug (@hubot, @octocat)"},
+		{"references", "A bug (#12).", "A bug by @octocat (#12)."},
+		{"period before references", "A bug. (#12)", "A bug by @octocat. (#12)"},
+		{"before references", "A bug by @hubot (#12)", "A bug by @hubot, @octocat (#12)"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			entry := Entry{Description: testCase.description, Authors: parseAuthors(testCase.description)}
+
+			entry.AddAuthor("@octocat")
+			entry.AddAuthor("@octocat")
+
+			if entry.Description != testCase.expectedDescription {
+				t.Errorf("expected description to be '%s', but was '%s'", testCase.expectedDescription, entry.Description)
+			}
+			if !reflect.DeepEqual(parseAuthors(entry.Description), entry.Authors) {
+				t.Errorf("expected authors %v to be parsed from the description, but was %v", entry.Authors, parseAuthors(entry.Description))
+			}
+		})
+	}
+}
+
+func TestAddAuthor_WhenDescriptionIsAttributedToAuthor_DoesNotAddAuthor(t *testing.T) {
+	entry := Entry{Description: "Bar by @alice."}
+
+	entry.AddAuthor("@Alice")
+
+	if entry.Description != "Bar by @alice." {
+		t.Errorf("expected description to be 'Bar by @alice.', but was '%s'", entry.Description)
+	}
+}
+
+func TestAddAuthor_WhenAuthorIsName_AttributesDescription(t *testing.T) {
+	testCases := []struct {
+		description         string
+		expectedDescription string
+		expectedAuthors     []string
+	}{
+		{"A bug.", "A bug by Jane Doe.", nil},
+		{"A bug (#12)", "A bug by Jane Doe (#12)", nil},
+		{"A bug by Jane Doe.", "A bug by Jane Doe.", nil},
+		{"A bug by @hubot.", "A bug by Jane Doe by @hubot.", []string{"@hubot"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			entry := Entry{Description: testCase.description, Authors: parseAuthors(testCase.description)}
+
+			entry.AddAuthor("Jane Doe")
+
+			if entry.Description != testCase.expectedDescription {
+				t.Errorf("expected description to be '%s', but was '%s'", testCase.expectedDescription, entry.Description)
+			}
+			if !reflect.DeepEqual(entry.Authors, testCase.expectedAuthors) || !reflect.DeepEqual(parseAuthors(entry.Description), testCase.expectedAuthors) {
+				t.Errorf("expected authors to be %v, but was %v", testCase.expectedAuthors, entry.Authors)
+			}
+		})
+	}
+}
+
+func TestAddReference_WhenEntryHasAuthors_AddsReferenceToGroup(t *testing.T) {
+	entry := Entry{Description: "A bug (#12) (@octocat)"}
+	entry.References = parseReferences(entry.Description)
+
+	entry.AddReference(Reference{Kind: IssueReference, ID: "13"})
+
+	if entry.Description != "A bug (#12, #13) (@octocat)" {
+		t.Errorf("expected reference to be added to the group, but was '%s'", entry.Description)
+	}
+}
+
+func TestContributors(t *testing.T) {
+	release := Release{Sections: []Section{
+		{Name: Added, Entries: []Entry{
+			{Authors: []string{"@octocat"}, Children: []Entry{{Authors: []string{"@jane"}}}},
+			{Authors: []string{"@hubot", "@Octocat"}},
+		}},
+		{Name: Fixed, Entries: []Entry{{}, {Authors: []string{"@jane"}}}},
+	}}
+
+	contributors := release.Contributors()
+
+	expectedContributors := []string{"@octocat", "@jane", "@hubot"}
+	if !reflect.DeepEqual(contributors, expectedContributors) {
+		t.Errorf("expected contributors to be %v, but was %v", expectedContributors, contributors)
+	}
+}
@@ -0,0 +1,89 @@
+package changelog
+
+import "strings"
+
+// BreakingMarker is the marker that the description of a breaking change starts
+// with, such as "**BREAKING** Dropped support for CHANGES.txt".
+const BreakingMarker = "**BREAKING**"
+
+// BreakingChanges is the name of the section that RenderRelease groups the
+// breaking changes of a release in, when asked to.
+const BreakingChanges = "Breaking changes"
+
+// defaultBreakingMarkers lists the markers that are always recognised.
+var defaultBreakingMarkers = []string{BreakingMarker, "BREAKING"}
+
+// WithBreakingMarker makes Parse recognise entries starting with the given
+// marker as breaking changes, besides those starting with "**BREAKING**".
+func WithBreakingMarker(marker string) ParseOption {
+	return func(stack *tokenStack) {
+		stack.breakingMarkers = append(stack.breakingMarkers, strings.TrimSpace(marker))
+	}
+}
+
+// isBreaking returns whether the description starts with one of the default
+// markers or one of the given markers.
+func isBreaking(description string, markers []string) bool {
+	for _, marker := range append(defaultBreakingMarkers, markers...) {
+		if marker != "" && strings.HasPrefix(description, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MarkBreaking marks the entry as a breaking change by prefixing its
+// description with the given marker, or with BreakingMarker when the marker is
+// empty. An entry that is already marked is left as is.
+func (e *Entry) MarkBreaking(marker string) {
+	if e.Breaking {
+		return
+	}
+	if marker == "" {
+		marker = BreakingMarker
+	}
+
+	e.Description = strings.TrimSpace(marker) + " " + e.Description
+	e.Breaking = true
+}
+
+// hasBreaking returns whether any of the entries, including nested entries, is
+// a breaking change.
+func hasBreaking(entries []Entry) bool {
+	for _, entry := range entries {
+		if entry.Breaking || hasBreaking(entry.Children) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// groupBreaking returns the release with its breaking changes moved from their
+// sections to a section of breaking changes before all others. Sections that
+// are left without entries are removed.
+func groupBreaking(release Release) Release {
+	breaking := Section{Name: BreakingChanges}
+	grouped := []Section{}
+	for _, section := range release.Sections {
+		entries := []Entry{}
+		for _, entry := range section.Entries {
+			if entry.Breaking {
+				breaking.Entries = append(breaking.Entries, entry)
+				continue
+			}
+			entries = append(entries, entry)
+		}
+		if len(entries) > 0 {
+			grouped = append(grouped, Section{Name: section.Name, Entries: entries})
+		}
+	}
+
+	if len(breaking.Entries) > 0 {
+		grouped = append([]Section{breaking}, grouped...)
+	}
+	release.Sections = grouped
+
+	return release
+}
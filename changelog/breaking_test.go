@@ -0,0 +1,114 @@
+package changelog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseBreakingChanges(t *testing.T) {
+	input := `# Changelog
+
+## [Unreleased]
+
+### Changed
+
+- **BREAKING** Renamed Parse to Read.
+- ⚠️ Dropped support for CHANGES.txt.
+- Improved errors.
+`
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	changelog, err := Parse(tokens, WithBreakingMarker("⚠️"))
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	entries := changelog.Unreleased.Changed()
+	expectedBreaking := []bool{true, true, false}
+	for i, expected := range expectedBreaking {
+		if entries[i].Breaking != expected {
+			t.Errorf("expected entry '%s' to be breaking '%t', but was '%t'", entries[i].Description, expected, entries[i].Breaking)
+		}
+	}
+}
+
+func TestMarkBreaking(t *testing.T) {
+	testCases := []struct {
+		marker              string
+		expectedDescription string
+	}{
+		{"", "**BREAKING** Renamed Parse to Read."},
+		{"⚠️", "⚠️ Renamed Parse to Read."},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expectedDescription, func(t *testing.T) {
+			entry := Entry{Description: "Renamed Parse to Read."}
+
+			entry.MarkBreaking(testCase.marker)
+			entry.MarkBreaking(testCase.marker)
+
+			if entry.Description != testCase.expectedDescription || !entry.Breaking {
+				t.Errorf("expected entry to be breaking with description '%s', but was '%s'", testCase.expectedDescription, entry.Description)
+			}
+		})
+	}
+}
+
+func TestRenderRelease_WhenGroupBreaking_RendersBreakingChangesFirst(t *testing.T) {
+	release := Release{Name: "2.0.0", Date: "2021-03-01", Sections: []Section{
+		{Name: Added, Entries: []Entry{{Description: "Invoices."}}},
+		{Name: Changed, Entries: []Entry{{Description: "**BREAKING** Renamed Parse to Read.", Breaking: true}}},
+	}}
+	out := &bytes.Buffer{}
+
+	err := RenderRelease(release, out, ReleaseOptions{OmitTitle: true, GroupBreaking: true})
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	expected := "### Breaking changes\n\n- **BREAKING** Renamed Parse to Read.\n\n### Added\n\n- Invoices.\n"
+	if out.String() != expected {
+		t.Errorf("expected release to be '%s', but was '%s'", expected, out.String())
+	}
+}
+
+func TestImportMarksBreakingChanges(t *testing.T) {
+	input := `{"schema": 1, "releases": [{"name": "2.0.0", "sections": [{"name": "Changed", "entries": [
+		{"description": "Renamed Parse to Read.", "breaking": true},
+		{"description": "**BREAKING** Dropped support for CHANGES.txt."}
+	]}]}]}`
+
+	changelog, err := Import(strings.NewReader(input), ExportJSON)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	entries := changelog.Releases[0].Changed()
+	if !entries[0].Breaking || entries[0].Description != "**BREAKING** Renamed Parse to Read." {
+		t.Errorf("expected entry to be marked as breaking, but was %v", entries[0])
+	}
+	if !entries[1].Breaking {
+		t.Errorf("expected entry with marker to be breaking, but was %v", entries[1])
+	}
+}
+
+func TestRenderRelease_WhenFormatIsHTML_HighlightsBreakingChanges(t *testing.T) {
+	release := Release{Name: "2.0.0", Sections: []Section{
+		{Name: Changed, Entries: []Entry{{Description: "**BREAKING** Renamed Parse to Read.", Breaking: true}}},
+	}}
+	out := &bytes.Buffer{}
+
+	err := RenderRelease(release, out, ReleaseOptions{Format: HTMLFormat, OmitTitle: true})
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if !strings.Contains(out.String(), `<li class="breaking"><strong>BREAKING</strong> Renamed Parse to Read.</li>`) {
+		t.Errorf("expected breaking change to be highlighted, but was '%s'", out.String())
+	}
+}
@@ -1,40 +1,222 @@
 package changelog
 
+import "strings"
+
+// The names of the sections defined by Keep a Changelog.
+const (
+	Added      = "Added"
+	Changed    = "Changed"
+	Deprecated = "Deprecated"
+	Removed    = "Removed"
+	Fixed      = "Fixed"
+	Security   = "Security"
+)
+
+// StandardSections lists the names of the sections defined by Keep a Changelog
+// in their canonical order.
+var StandardSections = []string{Added, Changed, Deprecated, Removed, Fixed, Security}
+
 // Changelog represents a projects changelog.
+//
+// The URL is the URL of the repository of the project, which the compare links
+// of the releases are created from by the link provider. The tags of releases
+// are their names prefixed with the tag prefix, such as "v".
+//
+// The struct tags define the names of the fields in the schema of Export,
+// which leaves out the fields that are derived from the others.
 type Changelog struct {
-	URL         string
-	Description string
-	Unreleased  Release
-	Releases    []Release
+	Title       string    `json:"title" yaml:"title"`
+	URL         string    `json:"url" yaml:"url"`
+	Description string    `json:"description" yaml:"description"`
+	Unreleased  Release   `json:"unreleased" yaml:"unreleased"`
+	Releases    []Release `json:"releases" yaml:"releases"`
+
+	LatestRelease Release `json:"-" yaml:"-"`
 
-	LatestRelease Release
+	Links     LinkProvider `json:"-" yaml:"-"`
+	TagPrefix string       `json:"tagPrefix,omitempty" yaml:"tagPrefix,omitempty"`
+
+	// document holds the original source of a changelog that was parsed with
+	// the Lossless option.
+	document *document
 }
 
-// Release represents a single release of a project.
+// Release represents a single release of a project. A release that was pulled
+// because of a serious bug or security issue is marked as yanked.
+//
+// The sections of a release are kept in the order they appear in the
+// changelog, and may have any name besides the standard ones.
 type Release struct {
-	Name string
-	Date string
+	Name   string `json:"name" yaml:"name"`
+	Date   string `json:"date,omitempty" yaml:"date,omitempty"`
+	Yanked bool   `json:"yanked,omitempty" yaml:"yanked,omitempty"`
+
+	PreviousRelease *Release `json:"-" yaml:"-"`
 
-	PreviousRelease *Release
+	Sections []Section `json:"sections" yaml:"sections"`
+}
 
-	Added      []Entry
-	Changed    []Entry
-	Deprecated []Entry
-	Removed    []Entry
-	Fixed      []Entry
-	Security   []Entry
+// Section represents a named group of entries of a release, such as "Added" or
+// "Fixed".
+type Section struct {
+	Name    string  `json:"name" yaml:"name"`
+	Entries []Entry `json:"entries" yaml:"entries"`
 }
 
-// Entry represents a single entry for a projects release. An entry must be part
-// of one of the sections "Added", "Changed", "Deprecated", "Removed", "Fixed"
-// or "Security".
+// Entry represents a single entry for a projects release.
+//
+// The description of an entry that wraps onto continuation lines contains a
+// newline for every continuation line. Entries may have nested child entries.
+// The references of an entry are parsed from the group of references that ends
+// its description, such as "(#123)", and its authors from the attribution that
+// ends it, such as "by @octocat", which are both kept in the description. An
+// entry is a breaking change when its description starts with a marker, such
+// as "**BREAKING**".
 type Entry struct {
-	Description string
+	Description string      `json:"description" yaml:"description"`
+	Breaking    bool        `json:"breaking,omitempty" yaml:"breaking,omitempty"`
+	References  []Reference `json:"references,omitempty" yaml:"references,omitempty"`
+	Authors     []string    `json:"authors,omitempty" yaml:"authors,omitempty"`
+	Children    []Entry     `json:"children,omitempty" yaml:"children,omitempty"`
 }
 
+// Section returns the section of the release with the given name, or nil if the
+// release has no such section.
+func (r *Release) Section(name string) *Section {
+	for i := range r.Sections {
+		if r.Sections[i].Name == name {
+			return &r.Sections[i]
+		}
+	}
+
+	return nil
+}
+
+// Entries returns the entries of the section with the given name.
+func (r Release) Entries(name string) []Entry {
+	if section := r.Section(name); section != nil {
+		return section.Entries
+	}
+
+	return nil
+}
+
+// AddEntry adds an entry to the section with the given name. A standard section
+// that does not exist yet is inserted in its canonical position, any other
+// section is added after the existing ones.
+func (r *Release) AddEntry(name string, entry Entry) {
+	if section := r.Section(name); section != nil {
+		section.Entries = append(section.Entries, entry)
+		return
+	}
+
+	index := len(r.Sections)
+	if rank := standardSectionRank(name); rank >= 0 {
+		for i, section := range r.Sections {
+			if otherRank := standardSectionRank(section.Name); otherRank > rank {
+				index = i
+				break
+			}
+		}
+	}
+
+	r.Sections = append(r.Sections, Section{})
+	copy(r.Sections[index+1:], r.Sections[index:])
+	r.Sections[index] = Section{Name: name, Entries: []Entry{entry}}
+}
+
+// Merge adds the entries of the other release to the sections of this release
+// with the same name, skipping entries that the section already contains.
+func (r *Release) Merge(other Release) {
+	for _, section := range other.Sections {
+		for _, entry := range section.Entries {
+			if existing := r.Section(section.Name); existing != nil && existing.contains(entry) {
+				continue
+			}
+			r.AddEntry(section.Name, entry)
+		}
+	}
+}
+
+// contains returns whether the section has an entry identical to the given
+// entry, including its nested entries.
+func (s Section) contains(entry Entry) bool {
+	for _, existing := range s.Entries {
+		if existing.equal(entry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e Entry) equal(other Entry) bool {
+	if e.Description != other.Description || len(e.Children) != len(other.Children) {
+		return false
+	}
+	for i := range e.Children {
+		if !e.Children[i].equal(other.Children[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Summary returns the description of the entry without its breaking marker,
+// attribution, group of references and final period, such as "Fixed a bug"
+// for "**BREAKING** Fixed a bug by @octocat (#12).". The given breaking markers
+// are recognised besides the default ones.
+func (e Entry) Summary(breakingMarkers ...string) string {
+	text := strings.TrimSpace(e.Description)
+	for _, marker := range append(append([]string{}, defaultBreakingMarkers...), breakingMarkers...) {
+		if marker = strings.TrimSpace(marker); marker != "" && strings.HasPrefix(text, marker) {
+			text = strings.TrimSpace(strings.TrimPrefix(text, marker))
+			break
+		}
+	}
+
+	text = trimAuthors(text)
+	if parseReferenceGroup(text) != nil {
+		text = text[:referenceGroupRegex.FindStringIndex(text)[0]]
+	}
+
+	return strings.TrimRight(trimAuthors(strings.TrimRight(text, " .")), " .")
+}
+
+// standardSectionRank returns the position of the section with the given name
+// in the canonical order, or -1 if it is not a standard section.
+func standardSectionRank(name string) int {
+	for i, standardSection := range StandardSections {
+		if standardSection == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Added returns the entries of the "Added" section.
+func (r Release) Added() []Entry { return r.Entries(Added) }
+
+// Changed returns the entries of the "Changed" section.
+func (r Release) Changed() []Entry { return r.Entries(Changed) }
+
+// Deprecated returns the entries of the "Deprecated" section.
+func (r Release) Deprecated() []Entry { return r.Entries(Deprecated) }
+
+// Removed returns the entries of the "Removed" section.
+func (r Release) Removed() []Entry { return r.Entries(Removed) }
+
+// Fixed returns the entries of the "Fixed" section.
+func (r Release) Fixed() []Entry { return r.Entries(Fixed) }
+
+// Security returns the entries of the "Security" section.
+func (r Release) Security() []Entry { return r.Entries(Security) }
+
 func newChangelog() Changelog {
 	return Changelog{
-		URL: "http://github.com/",
+		Title: "Changelog",
 		LatestRelease: Release{
 			Name: "HEAD",
 		},
@@ -0,0 +1,133 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReleaseStandardSectionAccessors(t *testing.T) {
+	release := Release{
+		Sections: []Section{
+			{Name: Added, Entries: []Entry{{Description: "Added"}}},
+			{Name: Changed, Entries: []Entry{{Description: "Changed"}}},
+			{Name: Deprecated, Entries: []Entry{{Description: "Deprecated"}}},
+			{Name: Removed, Entries: []Entry{{Description: "Removed"}}},
+			{Name: Fixed, Entries: []Entry{{Description: "Fixed"}}},
+			{Name: Security, Entries: []Entry{{Description: "Security"}}},
+		},
+	}
+	testCases := []struct {
+		name     string
+		accessor func() []Entry
+	}{
+		{Added, release.Added},
+		{Changed, release.Changed},
+		{Deprecated, release.Deprecated},
+		{Removed, release.Removed},
+		{Fixed, release.Fixed},
+		{Security, release.Security},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := testCase.accessor()
+
+			if len(result) != 1 || result[0].Description != testCase.name {
+				t.Errorf("expected the entries of section '%s', but got %v", testCase.name, result)
+			}
+		})
+	}
+}
+
+func TestReleaseEntriesOfMissingSectionIsEmpty(t *testing.T) {
+	release := Release{}
+
+	result := release.Entries("Performance")
+
+	if len(result) != 0 {
+		t.Errorf("expected no entries, but got %v", result)
+	}
+}
+
+func TestReleaseAddEntry(t *testing.T) {
+	testCases := []struct {
+		name             string
+		sections         []string
+		section          string
+		expectedSections []string
+	}{
+		{"to existing section", []string{Added, Fixed}, Fixed, []string{Added, Fixed}},
+		{"standard section in canonical position", []string{Added, Fixed}, Removed, []string{Added, Removed, Fixed}},
+		{"standard section before custom sections", []string{"Performance", Fixed}, Added, []string{"Performance", Added, Fixed}},
+		{"custom section after existing sections", []string{Added, Fixed}, "Performance", []string{Added, Fixed, "Performance"}},
+		{"to release without sections", []string{}, Security, []string{Security}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			release := Release{}
+			for _, name := range testCase.sections {
+				release.Sections = append(release.Sections, Section{Name: name, Entries: []Entry{{Description: "Existing entry."}}})
+			}
+
+			release.AddEntry(testCase.section, Entry{Description: "New entry."})
+
+			actualSections := []string{}
+			for _, section := range release.Sections {
+				actualSections = append(actualSections, section.Name)
+			}
+			if !reflect.DeepEqual(actualSections, testCase.expectedSections) {
+				t.Errorf("expected sections to be %v, but was %v", testCase.expectedSections, actualSections)
+			}
+			entries := release.Entries(testCase.section)
+			if entries[len(entries)-1].Description != "New entry." {
+				t.Errorf("expected entry to be added to section '%s', but was %v", testCase.section, entries)
+			}
+		})
+	}
+}
+
+func TestReleaseMergeSkipsIdenticalEntries(t *testing.T) {
+	release := Release{Sections: []Section{
+		{Name: Added, Entries: []Entry{{Description: "A feature.", Children: []Entry{{Description: "A detail."}}}}},
+		{Name: Fixed, Entries: []Entry{{Description: "A bug."}}},
+	}}
+	other := Release{Sections: []Section{
+		{Name: Added, Entries: []Entry{{Description: "A feature.", Children: []Entry{{Description: "A detail."}}}, {Description: "A feature."}}},
+		{Name: Removed, Entries: []Entry{{Description: "An option."}}},
+		{Name: Fixed, Entries: []Entry{{Description: "A bug."}, {Description: "Another bug."}}},
+	}}
+	expectedSections := []Section{
+		{Name: Added, Entries: []Entry{{Description: "A feature.", Children: []Entry{{Description: "A detail."}}}, {Description: "A feature."}}},
+		{Name: Removed, Entries: []Entry{{Description: "An option."}}},
+		{Name: Fixed, Entries: []Entry{{Description: "A bug."}, {Description: "Another bug."}}},
+	}
+
+	release.Merge(other)
+
+	if !reflect.DeepEqual(release.Sections, expectedSections) {
+		t.Errorf("expected sections to be %v, but was %v", expectedSections, release.Sections)
+	}
+}
+
+func TestEntrySummary(t *testing.T) {
+	testCases := []struct {
+		description     string
+		expectedSummary string
+	}{
+		{"Fixed a bug.", "Fixed a bug"},
+		{"**BREAKING** Fixed a bug by @octocat (#12).", "Fixed a bug"},
+		{"⚠️ Fixed a bug (#12) by @octocat, @hubot", "Fixed a bug"},
+		{"Fixed a bug (in the parser)", "Fixed a bug (in the parser)"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			summary := Entry{Description: testCase.description}.Summary("⚠️")
+
+			if summary != testCase.expectedSummary {
+				t.Errorf("expected summary to be '%s', but was '%s'", testCase.expectedSummary, summary)
+			}
+		})
+	}
+}
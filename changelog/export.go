@@ -0,0 +1,204 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the version of the schema that Export writes. It is
+// incremented whenever the schema changes in a way that older versions of
+// Import cannot read.
+const SchemaVersion = 1
+
+// ExportFormat is the format a changelog is exported to and imported from.
+type ExportFormat int
+
+// The formats a changelog can be exported to and imported from.
+const (
+	ExportJSON ExportFormat = iota
+	ExportYAML
+)
+
+// ParseExportFormat parses the name of an export format, one of "json" or
+// "yaml".
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return ExportJSON, nil
+	case "yaml", "yml":
+		return ExportYAML, nil
+	}
+
+	return ExportJSON, fmt.Errorf("unknown format '%s', must be one of json or yaml", name)
+}
+
+// exportedChangelog is the root of the schema of Export. Besides the fields of
+// the changelog, it holds the version of the schema and the compare links of
+// the releases, which the link provider and tag prefix are recognised from on
+// import.
+type exportedChangelog struct {
+	Schema    int `json:"schema" yaml:"schema"`
+	Changelog `yaml:",inline"`
+
+	Links []exportedLink `json:"links" yaml:"links"`
+}
+
+// exportedLink is the compare link of a release.
+type exportedLink struct {
+	Name string `json:"name" yaml:"name"`
+	URL  string `json:"url" yaml:"url"`
+}
+
+// Export writes the changelog to the writer as data in the given format, such
+// as:
+//
+//	{
+//	  "schema": 1,
+//	  "title": "Changelog",
+//	  "url": "https://github.com/mrombout/gochange",
+//	  "description": "All notable changes to this project will be documented in this file.",
+//	  "unreleased": {"name": "Unreleased", "sections": []},
+//	  "releases": [
+//	    {
+//	      "name": "1.0.0",
+//	      "date": "2021-03-01",
+//	      "yanked": true,
+//	      "sections": [
+//	        {"name": "Added", "entries": [{"description": "A feature.", "children": [{"description": "A detail."}]}]}
+//	      ]
+//	    }
+//	  ],
+//	  "links": [{"name": "Unreleased", "url": "https://github.com/mrombout/gochange/compare/1.0.0...HEAD"}]
+//	}
+//
+// Dates, the yanked flag, the tag prefix and nested entries are left out when
+// they are empty, and the links when the changelog has no URL.
+func Export(changelog Changelog, writer io.Writer, format ExportFormat) error {
+	exported := exportedChangelog{
+		Schema:    SchemaVersion,
+		Changelog: changelog,
+		Links:     []exportedLink{},
+	}
+	exported.Unreleased = exportedRelease(changelog.Unreleased)
+	exported.Releases = []Release{}
+	for _, release := range changelog.Releases {
+		exported.Releases = append(exported.Releases, exportedRelease(release))
+	}
+	if changelog.URL != "" {
+		exported.Links = append(exported.Links, exportedLink{Name: "Unreleased", URL: changelog.CompareURL(changelog.LatestRelease.Name, "HEAD")})
+	}
+	for _, release := range changelog.Releases {
+		if release.PreviousRelease != nil && changelog.URL != "" {
+			exported.Links = append(exported.Links, exportedLink{Name: release.Name, URL: changelog.CompareURL(release.PreviousRelease.Name, release.Name)})
+		}
+	}
+
+	if format == ExportYAML {
+		encoder := yaml.NewEncoder(writer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(exported); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(exported)
+}
+
+// exportedRelease returns the release with empty lists instead of nil ones, so
+// that they are exported as empty lists rather than null.
+func exportedRelease(release Release) Release {
+	result := release
+	result.Sections = []Section{}
+	for _, section := range release.Sections {
+		if section.Entries == nil {
+			section.Entries = []Entry{}
+		}
+		result.Sections = append(result.Sections, section)
+	}
+
+	return result
+}
+
+// Import reads a changelog that was written by Export in the given format. The
+// link provider and tag prefix are recognised from the compare links, as when
+// parsing a changelog.
+func Import(reader io.Reader, format ExportFormat) (Changelog, error) {
+	exported := exportedChangelog{}
+	var err error
+	if format == ExportYAML {
+		err = yaml.NewDecoder(reader).Decode(&exported)
+	} else {
+		err = json.NewDecoder(reader).Decode(&exported)
+	}
+	if err != nil {
+		return Changelog{}, err
+	}
+	if exported.Schema < 1 || exported.Schema > SchemaVersion {
+		return Changelog{}, fmt.Errorf("unsupported schema version %d, must be at most %d", exported.Schema, SchemaVersion)
+	}
+
+	changelog := exported.Changelog
+	if changelog.Unreleased.Name == "" {
+		changelog.Unreleased.Name = "Unreleased"
+	}
+	parseAllDetails(changelog.Unreleased.Sections)
+	for _, release := range changelog.Releases {
+		parseAllDetails(release.Sections)
+	}
+	changelog.LatestRelease = Release{Name: "HEAD"}
+	connectAllReleases(&changelog)
+	findAndSetLatestRelease(&changelog)
+
+	lines := []string{}
+	for _, link := range exported.Links {
+		lines = append(lines, fmt.Sprintf("[%s]: %s", link.Name, link.URL))
+	}
+	tokens, err := LexReader(strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		return changelog, err
+	}
+	url, tagPrefix := changelog.URL, changelog.TagPrefix
+	parseLinks(tokens, &changelog)
+	if url != "" {
+		changelog.URL = url
+	}
+	if tagPrefix != "" {
+		changelog.TagPrefix = tagPrefix
+	}
+
+	return changelog, nil
+}
+
+// parseAllDetails parses the references and authors of the entries of the
+// sections that were imported without them. Breaking changes are recognised by
+// their marker, and marked as such when they were imported without one.
+func parseAllDetails(sections []Section) {
+	var parse func(entries []Entry)
+	parse = func(entries []Entry) {
+		for i := range entries {
+			if entries[i].References == nil {
+				entries[i].References = parseReferences(entries[i].Description)
+			}
+			if entries[i].Authors == nil {
+				entries[i].Authors = parseAuthors(entries[i].Description)
+			}
+			if entries[i].Breaking && !isBreaking(entries[i].Description, nil) {
+				entries[i].Breaking = false
+				entries[i].MarkBreaking("")
+			}
+			entries[i].Breaking = entries[i].Breaking || isBreaking(entries[i].Description, nil)
+			parse(entries[i].Children)
+		}
+	}
+	for _, section := range sections {
+		parse(section.Entries)
+	}
+}
@@ -0,0 +1,154 @@
+package changelog
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+var exportFormats = []struct {
+	name   string
+	format ExportFormat
+	golden string
+}{
+	{"json", ExportJSON, "testdata/export.json"},
+	{"yaml", ExportYAML, "testdata/export.yaml"},
+}
+
+func TestExport(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/export.md")
+
+	for _, testCase := range exportFormats {
+		t.Run(testCase.name, func(t *testing.T) {
+			expectedOutput, err := ioutil.ReadFile(testCase.golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			output := strings.Builder{}
+
+			err = Export(changelog, &output, testCase.format)
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if output.String() != string(expectedOutput) {
+				t.Errorf("expected output to be\n%s\nbut was\n%s", expectedOutput, output.String())
+			}
+		})
+	}
+}
+
+func TestExportWithoutURLExportsNoLinks(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/export.md")
+	changelog.URL = ""
+	output := strings.Builder{}
+
+	err := Export(changelog, &output, ExportJSON)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if !strings.Contains(output.String(), "\"links\": []") {
+		t.Errorf("expected no links to be exported, but was\n%s", output.String())
+	}
+}
+
+func TestImport(t *testing.T) {
+	for _, testCase := range exportFormats {
+		t.Run(testCase.name, func(t *testing.T) {
+			file, err := os.Open(testCase.golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			changelog, err := Import(file, testCase.format)
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if changelog.Links != GitLab {
+				t.Errorf("expected link provider to be recognised as GitLab, but was %v", changelog.Links)
+			}
+			if changelog.TagPrefix != "v" {
+				t.Errorf("expected tag prefix to be 'v', but was '%s'", changelog.TagPrefix)
+			}
+			if changelog.LatestRelease.Name != "1.1.0" || !changelog.LatestRelease.Yanked {
+				t.Errorf("expected latest release to be yanked release 1.1.0, but was %v", changelog.LatestRelease)
+			}
+			if changelog.Releases[0].PreviousRelease == nil || changelog.Releases[0].PreviousRelease.Name != "1.0.0" {
+				t.Errorf("expected previous release of 1.1.0 to be 1.0.0, but was %v", changelog.Releases[0].PreviousRelease)
+			}
+			children := changelog.Releases[0].Added()[0].Children
+			if len(children) != 1 || children[0].Description != "With a detail." {
+				t.Errorf("expected nested entry to be imported, but was %v", children)
+			}
+
+			assertExportsAs(t, changelog, testCase.format, testCase.golden)
+		})
+	}
+}
+
+func assertExportsAs(t *testing.T, changelog Changelog, format ExportFormat, name string) {
+	t.Helper()
+
+	expectedOutput, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := strings.Builder{}
+
+	if err := Export(changelog, &output, format); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if output.String() != string(expectedOutput) {
+		t.Errorf("expected output to be\n%s\nbut was\n%s", expectedOutput, output.String())
+	}
+}
+
+func TestImportUnsupportedSchema(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{"missing", `{"title": "Changelog"}`},
+		{"newer", `{"schema": 2, "title": "Changelog"}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := Import(strings.NewReader(testCase.input), ExportJSON)
+
+			if err == nil {
+				t.Errorf("expected an error, but was nil")
+			}
+		})
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	testCases := []struct {
+		name           string
+		expectedFormat ExportFormat
+		expectError    bool
+	}{
+		{"json", ExportJSON, false},
+		{"YAML", ExportYAML, false},
+		{"yml", ExportYAML, false},
+		{"xml", ExportJSON, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			format, err := ParseExportFormat(testCase.name)
+
+			if (err != nil) != testCase.expectError {
+				t.Fatalf("expected error to be returned to be %t, but was '%v'", testCase.expectError, err)
+			}
+			if format != testCase.expectedFormat {
+				t.Errorf("expected format to be %d, but was %d", testCase.expectedFormat, format)
+			}
+		})
+	}
+}
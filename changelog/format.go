@@ -0,0 +1,265 @@
+package changelog
+
+import (
+	"sort"
+	"strings"
+)
+
+// Format returns the changelog in its canonical form. Sections are ordered as
+// in StandardSections, with other sections following in their original order,
+// and the standard section names are capitalised. Sections without entries are
+// left out and trailing whitespace is trimmed from the entries.
+//
+// The formatted changelog is rendered anew as a whole, which uses "-" bullets,
+// separates every title by a blank line and regenerates the compare links.
+// Content that is not part of the changelog model is kept: lines below the
+// title stay where they were, paragraphs below a release or section title stay
+// there, lines between entries stay after their entry and lines after the
+// entries of a section stay after them. Paragraphs of sections that are left
+// out move to their release. Link references that are not compare links are
+// kept below the compare links.
+func Format(changelog Changelog) Changelog {
+	result := changelog
+	result.Unreleased = formatRelease(changelog.Unreleased)
+	result.Releases = make([]Release, len(changelog.Releases))
+	for i, release := range changelog.Releases {
+		result.Releases[i] = formatRelease(release)
+	}
+	connectAllReleases(&result)
+	findAndSetLatestRelease(&result)
+
+	result.document = nil
+	if changelog.document != nil {
+		result.document = formattedDocument(result, changelog.document)
+	}
+
+	return result
+}
+
+// formatRelease returns the release with its sections in canonical order.
+func formatRelease(release Release) Release {
+	result := release
+	result.PreviousRelease = nil
+	result.Sections = []Section{}
+	for _, section := range release.Sections {
+		name := canonicalSectionName(section.Name)
+		for _, entry := range section.Entries {
+			result.AddEntry(name, formatEntry(entry))
+		}
+	}
+
+	sort.SliceStable(result.Sections, func(i, j int) bool {
+		return sectionRank(result.Sections[i].Name) < sectionRank(result.Sections[j].Name)
+	})
+
+	return result
+}
+
+// canonicalSectionName returns the name of the standard section with the given
+// name regardless of case, or the name itself for other sections.
+func canonicalSectionName(name string) string {
+	for _, standardSection := range StandardSections {
+		if strings.EqualFold(name, standardSection) {
+			return standardSection
+		}
+	}
+
+	return name
+}
+
+// sectionRank returns the position of a section in canonical order, which puts
+// sections that are not standard last.
+func sectionRank(name string) int {
+	if rank := standardSectionRank(name); rank >= 0 {
+		return rank
+	}
+
+	return len(StandardSections)
+}
+
+// formatEntry returns the entry with trailing whitespace trimmed from the lines
+// of its description and those of its children. Its other details are kept.
+func formatEntry(entry Entry) Entry {
+	lines := strings.Split(entry.Description, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	result := entry
+	result.Description = strings.Join(lines, "\n")
+	result.Children = nil
+	for _, child := range entry.Children {
+		result.Children = append(result.Children, formatEntry(child))
+	}
+
+	return result
+}
+
+// formattedDocument returns a document that renders the title and releases of
+// the given changelog canonically, with the lines of the given document that
+// are not part of the changelog model added to them. The lines below the title
+// are kept where they were, with single blank lines between their blocks, so
+// that the paragraphs of the description are not merged. It keeps the link
+// references of the given document that are not compare links, which are placed
+// after the compare links by a placeholder compare link. The line endings of
+// the given document are kept.
+func formattedDocument(changelog Changelog, doc *document) *document {
+	title, _ := renderFragment("title", changelog)
+
+	result := &document{
+		title:       changelog.Title,
+		description: doc.description,
+		preamble:    []string{strings.TrimSuffix(title, "\n"), ""},
+		links:       []linkNode{{compare: true}},
+
+		lineEnding:   doc.lineEnding,
+		finalNewline: true,
+	}
+
+	blank, inDescription := true, false
+	for i, line := range doc.preamble[1:] {
+		if strings.TrimSpace(line) == "" {
+			if blank {
+				continue
+			}
+			line = ""
+		} else if !blank && doc.descriptionLines[i+1] != inDescription {
+			result.preamble = append(result.preamble, "")
+		}
+		if doc.descriptionLines[i+1] {
+			if result.descriptionLines == nil {
+				result.descriptionLines = map[int]bool{}
+			}
+			result.descriptionLines[len(result.preamble)] = true
+		}
+		result.preamble = append(result.preamble, line)
+		blank = line == ""
+		if !blank {
+			inDescription = doc.descriptionLines[i+1]
+		}
+	}
+	if !blank {
+		result.preamble = append(result.preamble, "")
+	}
+
+	releases := append([]Release{changelog.Unreleased}, changelog.Releases...)
+	for i, node := range doc.releases {
+		if i >= len(releases) {
+			break
+		}
+		result.releases = append(result.releases, formattedRelease(releases[i], releaseTemplate(i), node))
+	}
+
+	for _, link := range doc.links {
+		if !link.compare && strings.TrimSpace(link.line) != "" {
+			result.links = append(result.links, link)
+		}
+	}
+
+	return result
+}
+
+// formattedRelease returns the node of the given formatted release, holding its
+// canonical lines with the unmodelled lines of the given node of the release
+// before it was formatted.
+func formattedRelease(release Release, template string, node *releaseNode) *releaseNode {
+	heads := map[string][]string{}
+	tails := map[string][]string{}
+	entryNotes := map[string][][]string{}
+	releaseNotes := trimBlankLines(node.head[1:])
+	for _, sectionNode := range node.sections {
+		name := canonicalSectionName(sectionNode.name)
+		notes := [][]string{}
+		for _, entryNode := range sectionNode.entries {
+			notes = append(notes, trimBlankLines(entryNode.lines[entryNode.size:]))
+		}
+
+		if section := release.Section(name); section == nil || len(section.Entries) == 0 {
+			releaseNotes = joinBlocks(releaseNotes, trimBlankLines(sectionNode.head[1:]))
+			for _, entryNotes := range notes {
+				releaseNotes = joinBlocks(releaseNotes, entryNotes)
+			}
+			releaseNotes = joinBlocks(releaseNotes, trimBlankLines(sectionNode.tail))
+			continue
+		}
+
+		heads[name] = joinBlocks(heads[name], trimBlankLines(sectionNode.head[1:]))
+		tails[name] = joinBlocks(tails[name], trimBlankLines(sectionNode.tail))
+		entryNotes[name] = append(entryNotes[name], notes...)
+	}
+
+	result := &releaseNode{name: release.Name}
+	result.fingerprint, _ = renderFragment(template, release)
+	result.titleFingerprint, _ = renderFragment("release title", release)
+	title := strings.TrimSuffix(result.titleFingerprint, "\n")
+	if template == "unreleased" {
+		title = "## [Unreleased]"
+	}
+	result.head = append([]string{title, ""}, withBlankLine(releaseNotes)...)
+
+	for _, section := range release.Sections {
+		sectionNode := &sectionNode{name: section.Name, head: []string{"### " + section.Name, ""}}
+		sectionNode.fingerprint, _ = renderFragment("section", section)
+		sectionNode.head = append(sectionNode.head, withBlankLine(heads[section.Name])...)
+
+		notes := entryNotes[section.Name]
+		tail := tails[section.Name]
+		for i, entry := range section.Entries {
+			entryNode := &entryNode{}
+			entryNode.fingerprint, _ = renderFragment("entry", entry)
+			entryNode.lines = strings.Split(strings.TrimSuffix(entryNode.fingerprint, "\n"), "\n")
+			entryNode.size = len(entryNode.lines)
+			if i < len(notes) && len(notes[i]) > 0 {
+				if i == len(section.Entries)-1 {
+					tail = joinBlocks(notes[i], tail)
+				} else {
+					entryNode.lines = append(entryNode.lines, "")
+					entryNode.lines = append(entryNode.lines, withBlankLine(notes[i])...)
+				}
+			}
+			sectionNode.entries = append(sectionNode.entries, entryNode)
+		}
+		sectionNode.tail = append([]string{""}, withBlankLine(tail)...)
+
+		result.sections = append(result.sections, sectionNode)
+	}
+
+	return result
+}
+
+// trimBlankLines returns the lines without leading and trailing blank lines.
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// joinBlocks returns the given blocks of lines separated by a blank line.
+func joinBlocks(first []string, second []string) []string {
+	if len(first) == 0 {
+		return second
+	}
+	if len(second) == 0 {
+		return first
+	}
+
+	result := append([]string{}, first...)
+	result = append(result, "")
+
+	return append(result, second...)
+}
+
+// withBlankLine returns the block of lines followed by a blank line, or nothing
+// when the block is empty.
+func withBlankLine(lines []string) []string {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	return append(append([]string{}, lines...), "")
+}
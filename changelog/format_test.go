@@ -0,0 +1,121 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/format.md")
+
+	assertRendersAs(t, Format(changelog), "testdata/format_formatted.md")
+}
+
+func TestFormatIsIdempotent(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/format_formatted.md")
+
+	assertRendersAs(t, Format(changelog), "testdata/format_formatted.md")
+}
+
+func TestFormatKeepsUnmodelledLines(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/format_notes.md")
+
+	assertRendersAs(t, Format(changelog), "testdata/format_notes_formatted.md")
+}
+
+func TestFormatKeepsUnmodelledLinesIdempotently(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/format_notes_formatted.md")
+
+	assertRendersAs(t, Format(changelog), "testdata/format_notes_formatted.md")
+}
+
+func TestFormatKeepsParagraphsAndCommentsOfThePreamble(t *testing.T) {
+	input := "# Changelog\n\n<!-- generated by gochange -->\n\nAll notable changes.\n\nThe format is based on Keep a Changelog.\n\n## [Unreleased]\n\n## [1.0.0] - 2020-01-01\n\n### Added\n\n- A feature.\n\n[Unreleased]: https://github.com/mrombout/gochange/compare/1.0.0...HEAD\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog, err := Parse(tokens, Lossless())
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	output := strings.Builder{}
+
+	if err := Render(Format(changelog), &output); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	if output.String() != input {
+		t.Errorf("expected output to be %q, but was %q", input, output.String())
+	}
+}
+
+func TestFormatWithoutLinksEndsWithTheLastEntry(t *testing.T) {
+	input := "# Changelog\n\n## [Unreleased]\n\n## [1.0.0] - 2020-01-01\n\n### Added\n\n- A feature.\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog, err := Parse(tokens, Lossless())
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	output := strings.Builder{}
+
+	if err := Render(Format(changelog), &output); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	if output.String() != input {
+		t.Errorf("expected output to be %q, but was %q", input, output.String())
+	}
+}
+
+func TestFormatOrdersSections(t *testing.T) {
+	release := formatRelease(Release{Sections: []Section{
+		{Name: "Improved", Entries: []Entry{{Description: "Improved"}}},
+		{Name: Fixed, Entries: []Entry{{Description: "Fixed"}}},
+		{Name: "added", Entries: []Entry{{Description: "Added"}}},
+		{Name: Removed},
+	}})
+
+	expectedNames := []string{Added, Fixed, "Improved"}
+	if len(release.Sections) != len(expectedNames) {
+		t.Fatalf("expected sections to be %v, but was %v", expectedNames, release.Sections)
+	}
+	for i, name := range expectedNames {
+		if release.Sections[i].Name != name {
+			t.Errorf("expected section %d to be '%s', but was '%s'", i, name, release.Sections[i].Name)
+		}
+	}
+}
+
+func TestFormatKeepsEntryDetails(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/format.md")
+	changelog.Unreleased.AddEntry(Changed, Entry{
+		Description: "**BREAKING** Dropped invoices by @octocat (#12)  ",
+		Breaking:    true,
+		References:  []Reference{{Kind: IssueReference, ID: "12"}},
+		Authors:     []string{"@octocat"},
+		Children:    []Entry{{Description: "Use receipts instead.", Breaking: true}},
+	})
+
+	formatted := Format(changelog)
+	entry := formatted.Unreleased.Section(Changed).Entries[0]
+
+	if entry.Description != "**BREAKING** Dropped invoices by @octocat (#12)" {
+		t.Errorf("expected description to be trimmed, but was '%s'", entry.Description)
+	}
+	if !entry.Breaking || !entry.Children[0].Breaking {
+		t.Errorf("expected entry and child to be breaking, but were not")
+	}
+	if len(entry.References) != 1 || entry.References[0].ID != "12" {
+		t.Errorf("expected references to be '[#12]', but was '%v'", entry.References)
+	}
+	if len(entry.Authors) != 1 || entry.Authors[0] != "@octocat" {
+		t.Errorf("expected authors to be '[@octocat]', but was '%v'", entry.Authors)
+	}
+	if bump := ReleaseBump(formatted.Unreleased); bump != BumpMajor {
+		t.Errorf("expected bump to be '%v', but was '%v'", BumpMajor, bump)
+	}
+}
@@ -0,0 +1,246 @@
+package changelog
+
+import (
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// htmlTemplate renders the parts of a changelog in HTML. The descriptions of
+// entries are converted from Markdown by inlineHTML.
+var htmlTemplate = template.Must(template.New("html").Funcs(template.FuncMap{
+	"sections":   sections,
+	"inline":     inlineHTML,
+	"paragraphs": paragraphs,
+	"anchor":     anchor,
+	"badge":      badge,
+}).Parse(`
+{{- define "page" -}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+{{template "style"}}
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+{{range paragraphs .Description}}<p>{{inline .}}</p>
+{{end -}}
+</header>
+{{template "toc" .}}
+<main>
+{{range .Releases}}{{template "page release" .}}{{end -}}
+</main>
+</body>
+</html>
+{{end -}}
+
+{{- define "style" -}}
+body { max-width: 48rem; margin: 0 auto; padding: 1rem; font-family: system-ui, sans-serif; line-height: 1.5; color: #24292f; }
+a { color: #0969da; }
+code { padding: 0.1em 0.3em; border-radius: 4px; background: #f0f2f4; font-size: 90%; }
+nav ul { padding-left: 1.2rem; }
+section.release { border-top: 1px solid #d0d7de; margin-top: 2rem; }
+h2 .anchor { visibility: hidden; margin-left: 0.3em; text-decoration: none; }
+h2:hover .anchor { visibility: visible; }
+time { color: #57606a; font-size: 80%; font-weight: normal; }
+.yanked { color: #cf222e; font-size: 70%; }
+.badge { display: inline-block; padding: 0.1em 0.6em; border-radius: 1em; color: #fff; font-size: 80%; background: #6e7781; }
+.badge-added { background: #1a7f37; }
+.badge-changed { background: #0969da; }
+.badge-deprecated { background: #9a6700; }
+.badge-removed { background: #cf222e; }
+.badge-fixed { background: #8250df; }
+.badge-security { background: #bc4c00; }
+li.breaking { color: #cf222e; }
+li.breaking li { color: #24292f; }
+{{- end -}}
+
+{{- define "toc" -}}
+<nav>
+<h2>Releases</h2>
+<ul>
+{{range .Releases}}<li><a href="#{{.Anchor}}">{{.Name}}</a>{{if .Date}} <time datetime="{{.Date}}">{{.Date}}</time>{{end}}</li>
+{{end -}}
+</ul>
+</nav>
+{{- end -}}
+
+{{- define "page release" -}}
+<section class="release" id="{{.Anchor}}">
+<h2>{{if .CompareURL}}<a href="{{.CompareURL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}
+{{- if .Date}} <time datetime="{{.Date}}">{{.Date}}</time>{{end}}
+{{- if .Yanked}} <span class="yanked">YANKED</span>{{end}} <a class="anchor" href="#{{.Anchor}}">#</a></h2>
+{{range sections .Release}}{{template "page section" .}}{{end -}}
+</section>
+{{end -}}
+
+{{- define "page section" -}}
+<h3><span class="badge {{badge .Name}}">{{.Name}}</span></h3>
+<ul>
+{{range .Entries}}{{template "entry" .}}{{end -}}
+</ul>
+{{end -}}
+
+{{- define "release" -}}
+{{template "release title" .}}
+{{template "sections" .}}
+{{- end -}}
+
+{{- define "release title" -}}
+<h2>{{.Name}}{{if .Date}} <time datetime="{{.Date}}">{{.Date}}</time>{{end}}{{if .Yanked}} <strong>[YANKED]</strong>{{end}}</h2>
+{{- end -}}
+
+{{- define "sections" -}}
+{{range sections .}}{{template "section" .}}{{end}}
+{{- end -}}
+
+{{- define "section" -}}
+<h3>{{.Name}}</h3>
+<ul>
+{{range .Entries}}{{template "entry" .}}{{end -}}
+</ul>
+{{end -}}
+
+{{- define "entry" -}}
+<li{{if .Breaking}} class="breaking"{{end}}>{{inline .Description}}
+{{- if .Children}}
+<ul>
+{{range .Children}}{{template "entry" .}}{{end -}}
+</ul>
+{{- end -}}
+</li>
+{{end -}}
+`))
+
+// htmlPage is the data of the page that RenderHTML renders.
+type htmlPage struct {
+	Title       string
+	Description string
+	Releases    []htmlRelease
+}
+
+// htmlRelease is a release on the page that RenderHTML renders, together with
+// the anchor it can be linked to and its compare link.
+type htmlRelease struct {
+	Release
+	Anchor     string
+	CompareURL string
+}
+
+// RenderHTML renders the changelog as a standalone HTML page to the given
+// writer. The page has a table of contents linking to the anchors of the
+// releases, shows the sections as badges and links every release to the
+// comparison with the release before it, if the changelog has a URL. The
+// unreleased changes are only shown when there are any.
+func RenderHTML(changelog Changelog, writer io.Writer) error {
+	page := htmlPage{
+		Title:       changelog.Title,
+		Description: changelog.Description,
+	}
+	if page.Title == "" {
+		page.Title = "Changelog"
+	}
+
+	if len(sections(changelog.Unreleased)) > 0 {
+		page.Releases = append(page.Releases, htmlRelease{Release: changelog.Unreleased, Anchor: anchor(changelog.Unreleased.Name)})
+		if changelog.URL != "" {
+			page.Releases[0].CompareURL = changelog.CompareURL(changelog.LatestRelease.Name, "HEAD")
+		}
+	}
+	for _, release := range changelog.Releases {
+		page.Releases = append(page.Releases, htmlRelease{Release: release, Anchor: anchor(release.Name)})
+		if release.PreviousRelease != nil && changelog.URL != "" {
+			page.Releases[len(page.Releases)-1].CompareURL = changelog.CompareURL(release.PreviousRelease.Name, release.Name)
+		}
+	}
+
+	return htmlTemplate.ExecuteTemplate(writer, "page", page)
+}
+
+var anchorRegex = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// anchor returns the id of the element of the release with the given name,
+// such as "unreleased" or "1.2.0".
+func anchor(name string) string {
+	return strings.Trim(anchorRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// badge returns the class of the badge of the section with the given name,
+// which is "badge-other" for sections that are not standard.
+func badge(name string) string {
+	if standardSectionRank(name) < 0 {
+		return "badge-other"
+	}
+
+	return "badge-" + strings.ToLower(name)
+}
+
+var paragraphRegex = regexp.MustCompile(`\n\s*\n`)
+
+// paragraphs splits Markdown text into its paragraphs, which are separated by
+// empty lines.
+func paragraphs(text string) []string {
+	result := []string{}
+	for _, paragraph := range paragraphRegex.Split(strings.TrimSpace(text), -1) {
+		if paragraph != "" {
+			result = append(result, paragraph)
+		}
+	}
+
+	return result
+}
+
+var codeSpanRegex = regexp.MustCompile("`[^`]+`")
+var strongRegex = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+var emphasisRegex = regexp.MustCompile(`\*([^*]+)\*`)
+var safeURLRegex = regexp.MustCompile(`^(?:https?://|mailto:|/|#|\.)`)
+
+// inlineHTML converts the inline Markdown of a description to HTML. Only code
+// spans, links, strong emphasis and emphasis are supported, any other text is
+// escaped. Links to URLs that are not web pages are rendered as their text.
+func inlineHTML(markdown string) template.HTML {
+	out := strings.Builder{}
+	last := 0
+	for _, span := range codeSpanRegex.FindAllStringIndex(markdown, -1) {
+		out.WriteString(inlineTextHTML(markdown[last:span[0]]))
+		out.WriteString("<code>" + template.HTMLEscapeString(markdown[span[0]+1:span[1]-1]) + "</code>")
+		last = span[1]
+	}
+	out.WriteString(inlineTextHTML(markdown[last:]))
+
+	return template.HTML(out.String())
+}
+
+// inlineTextHTML converts the links and emphasis of Markdown text without code
+// spans to HTML.
+func inlineTextHTML(markdown string) string {
+	out := strings.Builder{}
+	last := 0
+	for _, match := range markdownLinkRegex.FindAllStringSubmatchIndex(markdown, -1) {
+		out.WriteString(emphasisHTML(markdown[last:match[0]]))
+		text, url := markdown[match[2]:match[3]], markdown[match[4]:match[5]]
+		if safeURLRegex.MatchString(url) {
+			out.WriteString(`<a href="` + template.HTMLEscapeString(url) + `">` + emphasisHTML(text) + "</a>")
+		} else {
+			out.WriteString(emphasisHTML(text))
+		}
+		last = match[1]
+	}
+	out.WriteString(emphasisHTML(markdown[last:]))
+
+	return out.String()
+}
+
+func emphasisHTML(markdown string) string {
+	html := template.HTMLEscapeString(markdown)
+	html = strongRegex.ReplaceAllString(html, "<strong>$1</strong>")
+
+	return emphasisRegex.ReplaceAllString(html, "<em>$1</em>")
+}
@@ -1,7 +1,9 @@
 package changelog
 
 import (
-	"fmt"
+	"bufio"
+	"bytes"
+	"io"
 	"regexp"
 	"strings"
 )
@@ -14,37 +16,71 @@ type Scanner interface {
 }
 
 type token interface {
+	pos() position
+}
+
+// position records where in the source a token was found.
+type position struct {
+	Line   int
+	Column int
+
+	// Text is the raw line the token was lexed from.
+	Text string
+
+	// ending is the line ending that followed the line, such as "\r\n", or
+	// empty for a last line without one.
+	ending string
+}
+
+func (p position) pos() position {
+	return p
 }
 
 type header1Title struct {
+	position
 	Content string
 }
 
 type textLine struct {
+	position
 	Content string
 }
 
 type emptyLine struct {
+	position
 }
 
 type releaseTitle struct {
+	position
 	Content string
 	Date    string
+	Yanked  bool
 }
 
 type sectionTitle struct {
+	position
 	Content string
 }
 
 type changeEntry struct {
+	position
+	Content string
+}
+
+type entryContinuation struct {
+	position
 	Content string
 }
 
 type releaseCompareLink struct {
+	position
 	Title      string
 	URL        string
+	Repository string
 	FromTarget string
 	ToTarget   string
+
+	provider LinkProvider
 }
 
 // Lex lexes a changelog into logical tokens that makes parsing easier.
@@ -55,12 +91,35 @@ type releaseCompareLink struct {
 // - RELEASE_TITLE
 // - SECTION_TITLE
 // - CHANGE_ENTRY
+// - ENTRY_CONTINUATION
 // - RELEASE_COMPARE_LINK
+//
+// Every line is assumed to end with "\n". Use LexReader to record the actual
+// line endings of the changelog.
 func Lex(scanner Scanner) ([]token, error) {
+	return lex(scanner, false)
+}
+
+// LexReader lexes the changelog read from the given reader like Lex does, but
+// records the line endings of the changelog, so that a losslessly parsed
+// changelog is rendered with the same line endings.
+func LexReader(reader io.Reader) ([]token, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(scanLinesWithEndings)
+
+	return lex(scanner, true)
+}
+
+// lex lexes the lines of the given scanner. When keepsEndings is set, the
+// lines of the scanner are expected to include their line ending.
+func lex(scanner Scanner, keepsEndings bool) ([]token, error) {
 	tokens := []token{}
 
-	for scanner.Scan() {
-		line := scanner.Text()
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line, ending := scanner.Text(), "\n"
+		if keepsEndings {
+			line, ending = splitLineEnding(line)
+		}
 
 		var currentToken token
 		switch {
@@ -76,6 +135,8 @@ func Lex(scanner Scanner) ([]token, error) {
 			currentToken = lexSectionTitle(line)
 		case isChangeEntry(line):
 			currentToken = lexChangeEntry(line)
+		case isEntryContinuation(line):
+			currentToken = lexEntryContinuation(line)
 		case isReleaseCompareLink(line):
 			currentToken = lexReleaseCompareLink(line)
 		default:
@@ -83,7 +144,7 @@ func Lex(scanner Scanner) ([]token, error) {
 		}
 
 		if currentToken != nil {
-			tokens = append(tokens, currentToken)
+			tokens = append(tokens, withPosition(currentToken, position{Line: lineNumber, Column: indentation(line) + 1, Text: line, ending: ending}))
 		}
 	}
 
@@ -94,6 +155,93 @@ func Lex(scanner Scanner) ([]token, error) {
 	return tokens, nil
 }
 
+// scanLinesWithEndings is a split function like bufio.ScanLines that keeps the
+// line ending of every line.
+func scanLinesWithEndings(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if index := bytes.IndexByte(data, '\n'); index >= 0 {
+		return index + 1, data[:index+1], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
+
+// splitLineEnding returns the line without its line ending, and the line
+// ending.
+func splitLineEnding(line string) (string, string) {
+	for _, ending := range []string{"\r\n", "\n", "\r"} {
+		if strings.HasSuffix(line, ending) {
+			return strings.TrimSuffix(line, ending), ending
+		}
+	}
+
+	return line, ""
+}
+
+// withPosition returns a copy of the given token that records the position it
+// was found at.
+func withPosition(t token, p position) token {
+	switch t := t.(type) {
+	case header1Title:
+		t.position = p
+		return t
+	case textLine:
+		t.position = p
+		return t
+	case emptyLine:
+		t.position = p
+		return t
+	case releaseTitle:
+		t.position = p
+		return t
+	case sectionTitle:
+		t.position = p
+		return t
+	case changeEntry:
+		t.position = p
+		return t
+	case entryContinuation:
+		t.position = p
+		return t
+	case releaseCompareLink:
+		t.position = p
+		return t
+	}
+
+	return t
+}
+
+// describeToken returns a human readable name for the kind of the given token.
+func describeToken(t interface{}) string {
+	switch t.(type) {
+	case header1Title:
+		return "changelog title"
+	case textLine:
+		return "text line"
+	case emptyLine:
+		return "empty line"
+	case releaseTitle:
+		return "release title"
+	case sectionTitle:
+		return "section title"
+	case changeEntry:
+		return "change entry"
+	case entryContinuation:
+		return "entry continuation"
+	case releaseCompareLink:
+		return "release compare link"
+	case nil:
+		return "end of file"
+	}
+
+	return "unknown token"
+}
+
 func isHeader1Title(line string) bool {
 	return strings.HasPrefix(line, "# ")
 }
@@ -113,7 +261,7 @@ func lexEmptyLine(line string) emptyLine {
 }
 
 func isUnreleasedTitle(line string) bool {
-	return strings.HasPrefix(line, "## [") && strings.HasSuffix(line, "]")
+	return unreleasedTitleRegex.MatchString(line)
 }
 
 func lexUnreleasedTitle(line string) releaseTitle {
@@ -127,14 +275,17 @@ func isReleaseTitle(line string) bool {
 }
 
 func lexReleaseTitle(line string) releaseTitle {
-	title := ""
-	date := ""
-
-	fmt.Sscanf(line, "## %s - %s", &title, &date)
+	match := releaseTitleRegex.FindStringSubmatch(line)
+	if match == nil {
+		return releaseTitle{
+			Content: strings.TrimPrefix(line, "## "),
+		}
+	}
 
 	return releaseTitle{
-		Content: title[1 : len(title)-1],
-		Date:    date,
+		Content: match[1],
+		Date:    match[2],
+		Yanked:  match[3] != "",
 	}
 }
 
@@ -148,29 +299,59 @@ func lexSectionTitle(line string) sectionTitle {
 	}
 }
 
+// indentation returns the number of spaces and tabs a line starts with.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// isChangeEntry returns whether the line is a list item. Besides "-", the "*"
+// and "+" bullets are recognised, so that they can be normalised to "-".
 func isChangeEntry(line string) bool {
-	return strings.HasPrefix(line, "- ")
+	content := line[indentation(line):]
+
+	return strings.HasPrefix(content, "- ") || strings.HasPrefix(content, "* ") || strings.HasPrefix(content, "+ ")
 }
 
 func lexChangeEntry(line string) changeEntry {
 	return changeEntry{
-		Content: line[2:],
+		Content: line[indentation(line)+2:],
+	}
+}
+
+func isEntryContinuation(line string) bool {
+	indent := indentation(line)
+
+	return indent > 0 && indent < len(line)
+}
+
+func lexEntryContinuation(line string) entryContinuation {
+	return entryContinuation{
+		Content: line[indentation(line):],
 	}
 }
 
 func isReleaseCompareLink(line string) bool {
-	return strings.HasPrefix(line, "[")
+	return linkReferenceRegex.MatchString(line)
 }
 
+// lexReleaseCompareLink lexes a link reference. The compared targets are only
+// set when the URL is recognised as a compare link by one of the known link
+// providers.
 func lexReleaseCompareLink(line string) releaseCompareLink {
-	match := compareLinkRegex.FindStringSubmatch(line)
+	match := linkReferenceRegex.FindStringSubmatch(line)
+	link := releaseCompareLink{
+		Title: match[1],
+		URL:   strings.TrimSpace(match[2]),
+	}
 
-	return releaseCompareLink{
-		Title:      match[1],
-		URL:        match[2],
-		FromTarget: match[3],
-		ToTarget:   match[4],
+	if provider, repository, from, to, ok := parseCompareURL(link.URL); ok {
+		link.provider = provider
+		link.Repository = repository
+		link.FromTarget = from
+		link.ToTarget = to
 	}
+
+	return link
 }
 
 func lexTextLine(line string) textLine {
@@ -179,4 +360,6 @@ func lexTextLine(line string) textLine {
 	}
 }
 
-var compareLinkRegex = regexp.MustCompile(`\[(.*)\]: (https?:\/\/.*\/)(.*)\.\.\.(.*)`)
+var unreleasedTitleRegex = regexp.MustCompile(`^## \[[^\]]*\]$`)
+var releaseTitleRegex = regexp.MustCompile(`^## \[([^\]]*)\](?: - (\S+))?( \[YANKED\])?\s*$`)
+var linkReferenceRegex = regexp.MustCompile(`^\[([^\]]*)\]: (.*)`)
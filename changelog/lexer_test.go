@@ -13,25 +13,30 @@ func TestLexTokensIndividually(t *testing.T) {
 		line           string
 		expectedTokens []token
 	}{
-		{"# A Header 1", []token{header1Title{Content: "A Header 1"}}},
-		{"\r\n", []token{emptyLine{}}},
-		{"## [Unreleased]", []token{releaseTitle{Content: "Unreleased"}}},
-		{"## [0.0.1] - 2018-12-06", []token{releaseTitle{Content: "0.0.1", Date: "2018-12-06"}}},
-		{"### Added", []token{sectionTitle{Content: "Added"}}},
-		{"- A massive bug", []token{changeEntry{Content: "A massive bug"}}},
+		{"# A Header 1", []token{header1Title{position: position{Line: 1, Column: 1, Text: "# A Header 1"}, Content: "A Header 1"}}},
+		{"\r\n", []token{emptyLine{position: position{Line: 1, Column: 1, Text: "", ending: "\r\n"}}}},
+		{"## [Unreleased]", []token{releaseTitle{position: position{Line: 1, Column: 1, Text: "## [Unreleased]"}, Content: "Unreleased"}}},
+		{"## [0.0.1] - 2018-12-06", []token{releaseTitle{position: position{Line: 1, Column: 1, Text: "## [0.0.1] - 2018-12-06"}, Content: "0.0.1", Date: "2018-12-06"}}},
+		{"## [0.0.5] - 2014-12-13 [YANKED]", []token{releaseTitle{position: position{Line: 1, Column: 1, Text: "## [0.0.5] - 2014-12-13 [YANKED]"}, Content: "0.0.5", Date: "2014-12-13", Yanked: true}}},
+		{"### Added", []token{sectionTitle{position: position{Line: 1, Column: 1, Text: "### Added"}, Content: "Added"}}},
+		{"- A massive bug", []token{changeEntry{position: position{Line: 1, Column: 1, Text: "- A massive bug"}, Content: "A massive bug"}}},
+		{"  - A nested bug", []token{changeEntry{position: position{Line: 1, Column: 3, Text: "  - A nested bug"}, Content: "A nested bug"}}},
+		{"  that wraps", []token{entryContinuation{position: position{Line: 1, Column: 3, Text: "  that wraps"}, Content: "that wraps"}}},
 		{"[1.0.0]: https://github.com/olivierlacan/keep-a-changelog/compare/v0.3.0...v1.0.0", []token{releaseCompareLink{
+			position:   position{Line: 1, Column: 1, Text: "[1.0.0]: https://github.com/olivierlacan/keep-a-changelog/compare/v0.3.0...v1.0.0"},
 			Title:      "1.0.0",
-			URL:        "https://github.com/olivierlacan/keep-a-changelog/compare/",
+			URL:        "https://github.com/olivierlacan/keep-a-changelog/compare/v0.3.0...v1.0.0",
+			Repository: "https://github.com/olivierlacan/keep-a-changelog",
 			FromTarget: "v0.3.0",
 			ToTarget:   "v1.0.0",
+			provider:   GitHub,
 		}}},
-		{"This is a regular text line!", []token{textLine{Content: "This is a regular text line!"}}},
+		{"This is a regular text line!", []token{textLine{position: position{Line: 1, Column: 1, Text: "This is a regular text line!"}, Content: "This is a regular text line!"}}},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.line, func(t *testing.T) {
-			scanner := bufio.NewScanner(strings.NewReader(testCase.line))
-			tokens, err := Lex(scanner)
+			tokens, err := LexReader(strings.NewReader(testCase.line))
 
 			if err != nil {
 				t.Fatalf("expected result to be nil, but got %t", err)
@@ -51,6 +56,38 @@ func TestLexTokensIndividually(t *testing.T) {
 	}
 }
 
+func TestLexRecordsLineNumbers(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("# Changelog\n\nLorum ipsum.\n"))
+
+	tokens, err := Lex(scanner)
+
+	if err != nil {
+		t.Fatalf("expected result to be nil, but got %v", err)
+	}
+	for i, token := range tokens {
+		if token.pos().Line != i+1 {
+			t.Errorf("expected token %d to be on line %d, but was %d", i, i+1, token.pos().Line)
+		}
+	}
+}
+
+func TestLexScannerThatWasScannedBefore(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("<!-- generated -->\n# Changelog\n"))
+	scanner.Scan()
+
+	tokens, err := Lex(scanner)
+
+	if err != nil {
+		t.Fatalf("expected result to be nil, but got %v", err)
+	}
+	if len(tokens) != 1 {
+		t.Fatalf("expected to have lexed exactly 1 token, but was %d (%v)", len(tokens), tokens)
+	}
+	if _, ok := tokens[0].(header1Title); !ok {
+		t.Errorf("expected token to be a header 1 title, but was %v", tokens[0])
+	}
+}
+
 type ErroringScanner struct {
 }
 
@@ -136,7 +173,7 @@ func TestLexEmptyLine(t *testing.T) {
 
 	// assert
 	if result != expectedResult {
-		t.Errorf("expected result to be %s, but got %s", emptyLine{}, result)
+		t.Errorf("expected result to be %v, but got %v", emptyLine{}, result)
 	}
 }
 
@@ -147,6 +184,7 @@ func TestIsUnreleasedTitle(t *testing.T) {
 	}{
 		{"## [Unreleased]", true},
 		{"## [1.0.1] - 2018-12-24", false},
+		{"## [0.0.5] - 2014-12-13 [YANKED]", false},
 		{"## Header 2", false},
 	}
 
@@ -170,7 +208,7 @@ func TestLexUnreleasedTitle(t *testing.T) {
 
 	// assert
 	if result.Content != "Unreleased" {
-		t.Errorf("expected result to be %s, but got %s", "Unreleased", result)
+		t.Errorf("expected result to be %s, but got %v", "Unreleased", result)
 	}
 }
 
@@ -204,10 +242,29 @@ func TestLexReleaseTitle(t *testing.T) {
 
 	// assert
 	if result.Content != "v1.0.0" {
-		t.Errorf("expected result to be %s, but got %s", "v1.0.0", result)
+		t.Errorf("expected result to be %s, but got %v", "v1.0.0", result)
 	}
 	if result.Date != "2018-12-24" {
-		t.Errorf("expected result to be %s, but got %s", "2018-12-24", result)
+		t.Errorf("expected result to be %s, but got %v", "2018-12-24", result)
+	}
+}
+
+func TestLexYankedReleaseTitle(t *testing.T) {
+	// arrange
+	line := "## [0.0.5] - 2014-12-13 [YANKED]"
+
+	// act
+	result := lexReleaseTitle(line)
+
+	// assert
+	if result.Content != "0.0.5" {
+		t.Errorf("expected result to be %s, but got %v", "0.0.5", result)
+	}
+	if result.Date != "2014-12-13" {
+		t.Errorf("expected result to be %s, but got %v", "2014-12-13", result)
+	}
+	if !result.Yanked {
+		t.Errorf("expected result to be yanked, but got %v", result)
 	}
 }
 
@@ -255,7 +312,7 @@ func TestLexSectionTitle(t *testing.T) {
 			result := lexSectionTitle(testCase.line)
 
 			if result.Content != testCase.expectedContent {
-				t.Errorf("expected result to be %s, but got %s", testCase.expectedContent, result)
+				t.Errorf("expected result to be %s, but got %v", testCase.expectedContent, result)
 			}
 		})
 	}
@@ -266,8 +323,13 @@ func TestIsChangeEntry(t *testing.T) {
 		line           string
 		expectedResult bool
 	}{
-		{"* Not a change entry", false},
 		{"- A change entry", true},
+		{"* A change entry with an asterisk", true},
+		{"+ A change entry with a plus", true},
+		{"*emphasis*", false},
+		{"---", false},
+		{"  - A nested change entry", true},
+		{"  A continuation line", false},
 	}
 
 	for _, testCase := range testCases {
@@ -290,7 +352,69 @@ func TestLexChangeEntry(t *testing.T) {
 
 	// assert
 	if result.Content != "Added some stuff" {
-		t.Errorf("expected result to be %s, but got %s", "Added some stuff", result)
+		t.Errorf("expected result to be %s, but got %v", "Added some stuff", result)
+	}
+}
+
+func TestLexChangeEntryWithAsterisk(t *testing.T) {
+	// arrange
+	line := "* Added some stuff"
+
+	// act
+	result := lexChangeEntry(line)
+
+	// assert
+	if result.Content != "Added some stuff" {
+		t.Errorf("expected result to be %s, but got %v", "Added some stuff", result)
+	}
+}
+
+func TestLexNestedChangeEntry(t *testing.T) {
+	// arrange
+	line := "    - Added some nested stuff"
+
+	// act
+	result := lexChangeEntry(line)
+
+	// assert
+	if result.Content != "Added some nested stuff" {
+		t.Errorf("expected result to be %s, but got %v", "Added some nested stuff", result)
+	}
+}
+
+func TestIsEntryContinuation(t *testing.T) {
+	testCases := []struct {
+		line           string
+		expectedResult bool
+	}{
+		{"  that continues here", true},
+		{"\tthat continues here", true},
+		{"Not a continuation", false},
+		{"   ", false},
+		{"", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.line, func(t *testing.T) {
+			result := isEntryContinuation(testCase.line)
+
+			if result != testCase.expectedResult {
+				t.Errorf("expected result to be %t, but got %t", testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestLexEntryContinuation(t *testing.T) {
+	// arrange
+	line := "  that continues here"
+
+	// act
+	result := lexEntryContinuation(line)
+
+	// assert
+	if result.Content != "that continues here" {
+		t.Errorf("expected result to be %s, but got %v", "that continues here", result)
 	}
 }
 
@@ -301,6 +425,7 @@ func TestIsReleaseCompareLink(t *testing.T) {
 	}{
 		{"[Unreleased]: https://golang.org/", true},
 		{"[v1.0.0]: https://golang.org/", true},
+		{"[![badge](https://golang.org/badge.svg)](https://golang.org/)", false},
 	}
 
 	for _, testCase := range testCases {
@@ -323,16 +448,35 @@ func TestLexReleaseCompareLink(t *testing.T) {
 
 	// assert
 	if result.Title != "v1.0.0" {
-		t.Errorf("expected result to be %s, but got %s", "v1.0.0", result)
+		t.Errorf("expected result to be %s, but got %v", "v1.0.0", result)
 	}
-	if result.URL != "https://golang.org/" {
-		t.Errorf("expected result to be %s, but got %s", "https://golang.org/", result)
+	if result.Repository != "https://golang.org" {
+		t.Errorf("expected result to be %s, but got %v", "https://golang.org", result)
 	}
 	if result.FromTarget != "v1.0.0" {
-		t.Errorf("expected result to be %s, but got %s", "v1.0.0", result)
+		t.Errorf("expected result to be %s, but got %v", "v1.0.0", result)
 	}
 	if result.ToTarget != "HEAD" {
-		t.Errorf("expected result to be %s, but got %s", "HEAD", result)
+		t.Errorf("expected result to be %s, but got %v", "HEAD", result)
+	}
+}
+
+func TestLexReleaseCompareLinkWithoutCompareTargets(t *testing.T) {
+	// arrange
+	line := "[homepage]: https://golang.org/"
+
+	// act
+	result := lexReleaseCompareLink(line)
+
+	// assert
+	if result.Title != "homepage" {
+		t.Errorf("expected result to be %s, but got %v", "homepage", result)
+	}
+	if result.URL != "https://golang.org/" {
+		t.Errorf("expected result to be %s, but got %v", "https://golang.org/", result)
+	}
+	if result.FromTarget != "" || result.ToTarget != "" {
+		t.Errorf("expected result to have no targets, but got %v", result)
 	}
 }
 
@@ -345,6 +489,6 @@ func TestLexTextLine(t *testing.T) {
 
 	// assert
 	if result.Content != line {
-		t.Errorf("expected result to be %s, but got %s", line, result)
+		t.Errorf("expected result to be %s, but got %v", line, result)
 	}
 }
@@ -0,0 +1,160 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LinkProvider creates the links that compare two versions of a repository, and
+// recognises them again when a changelog is parsed.
+type LinkProvider interface {
+	// CompareURL returns the URL that compares the from and to tags of the
+	// repository.
+	CompareURL(repository, from, to string) string
+	// ParseCompareURL returns the repository and the compared tags of the given
+	// URL, or false if the URL is not a compare link of this provider.
+	ParseCompareURL(url string) (repository, from, to string, ok bool)
+}
+
+// templateLinkProvider is a LinkProvider that creates links by replacing the
+// placeholders {repo}, {from} and {to} of a template.
+type templateLinkProvider struct {
+	template string
+	regex    *regexp.Regexp
+	groups   map[string]int
+}
+
+// The link providers of the supported forges.
+var (
+	GitHub    = mustLinkProvider("{repo}/compare/{from}...{to}")
+	GitLab    = mustLinkProvider("{repo}/-/compare/{from}...{to}")
+	Bitbucket = mustLinkProvider("{repo}/branches/compare/{to}%0D{from}")
+	Gitea     = mustLinkProvider("{repo}/compare/{from}...{to}")
+)
+
+// legacyLinkProvider recognises the compare links of hosts that append the
+// compared tags to the repository URL directly.
+var legacyLinkProvider = mustLinkProvider("{repo}/{from}...{to}")
+
+// knownLinkProviders lists the link providers that are recognised when lexing,
+// the more specific ones first. Gitea is left out since its compare links are
+// the same as those of GitHub, give it with WithLinkProvider instead.
+var knownLinkProviders = []LinkProvider{GitLab, Bitbucket, GitHub, legacyLinkProvider}
+
+// linkProviders maps the names of the supported forges to their link provider.
+var linkProviders = map[string]LinkProvider{
+	"github":    GitHub,
+	"gitlab":    GitLab,
+	"bitbucket": Bitbucket,
+	"gitea":     Gitea,
+}
+
+var placeholderRegex = regexp.MustCompile(`\\\{(repo|from|to)\\\}`)
+
+// NewLinkProvider returns a LinkProvider for a custom host from a template such
+// as "{repo}/compare/{from}..{to}". The template must contain the {from} and
+// {to} placeholders, and may contain the {repo} placeholder.
+func NewLinkProvider(template string) (LinkProvider, error) {
+	if !strings.Contains(template, "{from}") || !strings.Contains(template, "{to}") {
+		return nil, errors.New("compare link template must contain {from} and {to}")
+	}
+
+	groups := map[string]int{}
+	pattern := placeholderRegex.ReplaceAllStringFunc(regexp.QuoteMeta(template), func(placeholder string) string {
+		name := placeholderRegex.FindStringSubmatch(placeholder)[1]
+		if _, ok := groups[name]; ok {
+			return `\S+?`
+		}
+		groups[name] = len(groups) + 1
+		if name == "repo" {
+			return `(https?://\S+)`
+		}
+
+		return `(\S+?)`
+	})
+
+	regex, err := regexp.Compile("^" + pattern + "$")
+	if err != nil {
+		return nil, err
+	}
+
+	return &templateLinkProvider{template: template, regex: regex, groups: groups}, nil
+}
+
+func mustLinkProvider(template string) LinkProvider {
+	provider, err := NewLinkProvider(template)
+	if err != nil {
+		panic(err)
+	}
+
+	return provider
+}
+
+// LinkProviderByName returns the link provider of the forge with the given
+// name, one of "github", "gitlab", "bitbucket" or "gitea".
+func LinkProviderByName(name string) (LinkProvider, error) {
+	if provider, ok := linkProviders[strings.ToLower(name)]; ok {
+		return provider, nil
+	}
+
+	return nil, fmt.Errorf("unknown link provider '%s', must be one of github, gitlab, bitbucket or gitea", name)
+}
+
+func (p *templateLinkProvider) CompareURL(repository, from, to string) string {
+	return strings.NewReplacer("{repo}", repository, "{from}", from, "{to}", to).Replace(p.template)
+}
+
+func (p *templateLinkProvider) ParseCompareURL(url string) (string, string, string, bool) {
+	match := p.regex.FindStringSubmatch(url)
+	if match == nil {
+		return "", "", "", false
+	}
+
+	group := func(name string) string {
+		if index, ok := p.groups[name]; ok {
+			return match[index]
+		}
+		return ""
+	}
+
+	return group("repo"), group("from"), group("to"), true
+}
+
+// parseCompareURL returns the compare link of the first known link provider
+// that recognises the given URL.
+func parseCompareURL(url string) (provider LinkProvider, repository, from, to string, ok bool) {
+	for _, provider := range knownLinkProviders {
+		if repository, from, to, ok := provider.ParseCompareURL(url); ok {
+			return provider, repository, from, to, true
+		}
+	}
+
+	return nil, "", "", "", false
+}
+
+// linkProvider returns the link provider of the changelog, GitHub if none is
+// set.
+func (c Changelog) linkProvider() LinkProvider {
+	if c.Links != nil {
+		return c.Links
+	}
+
+	return GitHub
+}
+
+// Tag returns the tag of the release with the given name, which is the name
+// prefixed with the tag prefix of the changelog. HEAD is never prefixed.
+func (c Changelog) Tag(name string) string {
+	if name == "HEAD" {
+		return name
+	}
+
+	return c.TagPrefix + name
+}
+
+// CompareURL returns the URL that compares the releases with the given names.
+func (c Changelog) CompareURL(from, to string) string {
+	return c.linkProvider().CompareURL(c.URL, c.Tag(from), c.Tag(to))
+}
@@ -0,0 +1,228 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLinkProviderCompareURL(t *testing.T) {
+	testCases := []struct {
+		name        string
+		provider    LinkProvider
+		expectedURL string
+	}{
+		{"github", GitHub, "https://github.com/owner/repo/compare/v1.0.0...v1.1.0"},
+		{"gitlab", GitLab, "https://gitlab.com/group/project/-/compare/v1.0.0...v1.1.0"},
+		{"bitbucket", Bitbucket, "https://bitbucket.org/owner/repo/branches/compare/v1.1.0%0Dv1.0.0"},
+		{"gitea", Gitea, "https://gitea.com/owner/repo/compare/v1.0.0...v1.1.0"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			repository := testCase.expectedURL[:strings.Index(testCase.expectedURL, "/compare")]
+			repository = strings.TrimSuffix(strings.TrimSuffix(repository, "/-"), "/branches")
+
+			result := testCase.provider.CompareURL(repository, "v1.0.0", "v1.1.0")
+
+			if result != testCase.expectedURL {
+				t.Errorf("expected URL to be '%s', but was '%s'", testCase.expectedURL, result)
+			}
+		})
+	}
+}
+
+func TestLexReleaseCompareLinkOfKnownProviders(t *testing.T) {
+	testCases := []struct {
+		line               string
+		expectedRepository string
+		expectedFrom       string
+		expectedTo         string
+	}{
+		{"[1.1.0]: https://github.com/owner/repo/compare/v1.0.0...v1.1.0", "https://github.com/owner/repo", "v1.0.0", "v1.1.0"},
+		{"[1.1.0]: https://gitlab.com/group/sub/project/-/compare/v1.0.0...v1.1.0", "https://gitlab.com/group/sub/project", "v1.0.0", "v1.1.0"},
+		{"[1.1.0]: https://bitbucket.org/owner/repo/branches/compare/v1.1.0%0Dv1.0.0", "https://bitbucket.org/owner/repo", "v1.0.0", "v1.1.0"},
+		{"[Unreleased]: https://gitea.example.com/owner/repo/compare/1.1.0...HEAD", "https://gitea.example.com/owner/repo", "1.1.0", "HEAD"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.line, func(t *testing.T) {
+			result := lexReleaseCompareLink(testCase.line)
+
+			if result.Repository != testCase.expectedRepository {
+				t.Errorf("expected repository to be '%s', but was '%s'", testCase.expectedRepository, result.Repository)
+			}
+			if result.FromTarget != testCase.expectedFrom || result.ToTarget != testCase.expectedTo {
+				t.Errorf("expected targets to be '%s...%s', but was '%s...%s'", testCase.expectedFrom, testCase.expectedTo, result.FromTarget, result.ToTarget)
+			}
+		})
+	}
+}
+
+func TestNewLinkProviderRequiresTargets(t *testing.T) {
+	_, err := NewLinkProvider("{repo}/compare/{to}")
+
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestLinkProviderByName(t *testing.T) {
+	provider, err := LinkProviderByName("GitLab")
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if provider != GitLab {
+		t.Errorf("expected provider to be GitLab, but was %v", provider)
+	}
+	if _, err := LinkProviderByName("sourceforge"); err == nil {
+		t.Errorf("expected an error for an unknown provider, but was nil")
+	}
+}
+
+const prefixedChangelog = `# Changelog
+
+Lorum ipsum.
+
+## [Unreleased]
+
+## [1.1.0] - 2021-02-01
+
+### Fixed
+
+- A bug.
+
+## [1.0.0] - 2021-01-01
+
+### Added
+
+- A feature.
+
+[Unreleased]: %s
+[1.1.0]: %s
+`
+
+func parseLinksChangelog(t *testing.T, unreleasedURL, releaseURL string, options ...ParseOption) Changelog {
+	t.Helper()
+
+	input := strings.Replace(strings.Replace(prefixedChangelog, "%s", unreleasedURL, 1), "%s", releaseURL, 1)
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog, err := Parse(tokens, options...)
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	return changelog
+}
+
+func TestParseDetectsLinkProviderAndTagPrefix(t *testing.T) {
+	changelog := parseLinksChangelog(t,
+		"https://gitlab.com/group/project/-/compare/v1.1.0...HEAD",
+		"https://gitlab.com/group/project/-/compare/v1.0.0...v1.1.0")
+
+	if changelog.URL != "https://gitlab.com/group/project" {
+		t.Errorf("expected URL to be 'https://gitlab.com/group/project', but was '%s'", changelog.URL)
+	}
+	if changelog.Links != GitLab {
+		t.Errorf("expected link provider to be GitLab, but was %v", changelog.Links)
+	}
+	if changelog.TagPrefix != "v" {
+		t.Errorf("expected tag prefix to be 'v', but was '%s'", changelog.TagPrefix)
+	}
+
+	output := strings.Builder{}
+	if err := Render(changelog, &output); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if !strings.HasSuffix(output.String(), "[Unreleased]: https://gitlab.com/group/project/-/compare/v1.1.0...HEAD\n[1.1.0]: https://gitlab.com/group/project/-/compare/v1.0.0...v1.1.0\n") {
+		t.Errorf("expected links to be rendered in the same shape, but was\n%s", output.String())
+	}
+}
+
+func TestParseWithCustomLinkProvider(t *testing.T) {
+	provider, err := NewLinkProvider("{repo}/diff?from={from}&to={to}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changelog := parseLinksChangelog(t,
+		"https://git.example.com/project/diff?from=release-1.1.0&to=HEAD",
+		"https://git.example.com/project/diff?from=release-1.0.0&to=release-1.1.0",
+		WithLinkProvider(provider))
+
+	if changelog.URL != "https://git.example.com/project" {
+		t.Errorf("expected URL to be 'https://git.example.com/project', but was '%s'", changelog.URL)
+	}
+	if changelog.TagPrefix != "release-" {
+		t.Errorf("expected tag prefix to be 'release-', but was '%s'", changelog.TagPrefix)
+	}
+	if url := changelog.CompareURL("1.1.0", "1.2.0"); url != "https://git.example.com/project/diff?from=release-1.1.0&to=release-1.2.0" {
+		t.Errorf("expected compare URL to use the custom template, but was '%s'", url)
+	}
+}
+
+func TestParseWithUnrecognisedLinksLeavesURLEmpty(t *testing.T) {
+	changelog := parseLinksChangelog(t,
+		"https://git.example.com/project/diff?from=release-1.1.0&to=HEAD",
+		"https://git.example.com/project/diff?from=release-1.0.0&to=release-1.1.0")
+
+	if changelog.URL != "" {
+		t.Errorf("expected URL to be empty, but was '%s'", changelog.URL)
+	}
+
+	output := strings.Builder{}
+	if err := Render(changelog, &output); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if strings.Contains(output.String(), "[Unreleased]:") {
+		t.Errorf("expected no compare links to be rendered, but was\n%s", output.String())
+	}
+}
+
+func TestParseWithLowercaseUnreleasedLinkDetectsURL(t *testing.T) {
+	input := "# Changelog\n\n## [Unreleased]\n\n## [1.1.1] - 2021-01-01\n\n### Fixed\n\n- A bug.\n\n[unreleased]: https://github.com/acme/widget/compare/v1.1.1...HEAD\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	changelog, err := Parse(tokens)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if changelog.URL != "https://github.com/acme/widget" {
+		t.Errorf("expected URL to be 'https://github.com/acme/widget', but was '%s'", changelog.URL)
+	}
+	if url := changelog.CompareURL("1.1.1", "1.2.0"); url != "https://github.com/acme/widget/compare/v1.1.1...v1.2.0" {
+		t.Errorf("expected compare URL of the repository, but was '%s'", url)
+	}
+}
+
+func TestParseWithoutLinksCreatesNoCompareLinks(t *testing.T) {
+	input := "# Changelog\n\n## [Unreleased]\n\n## [1.1.0] - 2021-01-01\n\n### Added\n\n- A feature.\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog, err := Parse(tokens)
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	output := strings.Builder{}
+	err = Render(changelog, &output)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if changelog.URL != "" {
+		t.Errorf("expected URL to be empty, but was '%s'", changelog.URL)
+	}
+	if strings.Contains(output.String(), "[Unreleased]:") {
+		t.Errorf("expected no compare links to be rendered, but was\n%s", output.String())
+	}
+}
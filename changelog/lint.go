@@ -0,0 +1,296 @@
+package changelog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LintIssue is a violation of a lint rule, found at the given line of the
+// changelog. The line is 0 when it is not known.
+type LintIssue struct {
+	Rule    string
+	Line    int
+	Message string
+}
+
+// LintRule is a convention of Keep a Changelog that a changelog is checked
+// against.
+type LintRule struct {
+	Name        string
+	Description string
+
+	check func(linter *linter)
+}
+
+// LintRules lists all lint rules.
+//
+// The rules about compare links can only check a changelog that was parsed
+// with the Lossless option, because only then the links are kept.
+var LintRules = []LintRule{
+	{"date-format", "releases are dated with an ISO 8601 date", checkDateFormat},
+	{"date-order", "releases are ordered from the newest to the oldest date", checkDateOrder},
+	{"version-format", "releases are named after a semantic version", checkVersionFormat},
+	{"version-order", "releases are ordered from the highest to the lowest version", checkVersionOrder},
+	{"duplicate-version", "every version is released once", checkDuplicateVersion},
+	{"empty-release", "releases have entries", checkEmptyRelease},
+	{"empty-section", "sections have entries", checkEmptySection},
+	{"section-name", "sections are named after the types of changes of Keep a Changelog", checkSectionName},
+	{"compare-link", "releases have a compare link", checkCompareLink},
+	{"link-chain", "compare links compare a release with the release before it", checkLinkChain},
+}
+
+// Lint checks the changelog against the lint rules with the given names, or
+// against all lint rules when no names are given. The issues are ordered by
+// line.
+func Lint(changelog Changelog, rules ...string) ([]LintIssue, error) {
+	selected := LintRules
+	if len(rules) > 0 {
+		selected = []LintRule{}
+		for _, name := range rules {
+			rule, ok := findLintRule(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown lint rule '%s'", name)
+			}
+			selected = append(selected, rule)
+		}
+	}
+
+	linter := &linter{changelog: changelog}
+	for _, rule := range selected {
+		linter.rule = rule.Name
+		rule.check(linter)
+	}
+	sort.SliceStable(linter.issues, func(i, j int) bool {
+		return linter.issues[i].Line < linter.issues[j].Line
+	})
+
+	return linter.issues, nil
+}
+
+func findLintRule(name string) (LintRule, bool) {
+	for _, rule := range LintRules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+
+	return LintRule{}, false
+}
+
+// linter collects the issues of the rule that is being checked.
+type linter struct {
+	changelog Changelog
+	rule      string
+	issues    []LintIssue
+}
+
+func (l *linter) report(line int, format string, args ...interface{}) {
+	l.issues = append(l.issues, LintIssue{
+		Rule:    l.rule,
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// releases returns the unreleased changes followed by the releases.
+func (l *linter) releases() []Release {
+	return append([]Release{l.changelog.Unreleased}, l.changelog.Releases...)
+}
+
+// releaseNode returns the node of the release at the given index, where the
+// unreleased changes come first, or nil if it is not known.
+func (l *linter) releaseNode(index int) *releaseNode {
+	doc := l.changelog.document
+	if doc == nil || index >= len(doc.releases) {
+		return nil
+	}
+	if index > 0 && doc.releases[index].name != l.changelog.Releases[index-1].Name {
+		return nil
+	}
+
+	return doc.releases[index]
+}
+
+// releaseLine returns the line of the title of the release at the given index.
+func (l *linter) releaseLine(index int) int {
+	if node := l.releaseNode(index); node != nil {
+		return node.line
+	}
+
+	return 0
+}
+
+// sectionLine returns the line of the title of the section with the given name
+// of the release at the given index.
+func (l *linter) sectionLine(index int, name string) int {
+	node := l.releaseNode(index)
+	if node == nil {
+		return 0
+	}
+	for _, section := range node.sections {
+		if section.name == name {
+			return section.line
+		}
+	}
+
+	return node.line
+}
+
+// displayName returns the name of the release at the given index as it is
+// used in messages.
+func displayName(index int, release Release) string {
+	if index == 0 {
+		return "Unreleased"
+	}
+
+	return release.Name
+}
+
+func checkDateFormat(l *linter) {
+	for i, release := range l.changelog.Releases {
+		if release.Date == "" {
+			l.report(l.releaseLine(i+1), "release %s has no date", release.Name)
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", release.Date); err != nil {
+			l.report(l.releaseLine(i+1), "date '%s' of release %s is not an ISO 8601 date", release.Date, release.Name)
+		}
+	}
+}
+
+func checkDateOrder(l *linter) {
+	var newer *Release
+	var newerDate time.Time
+	for i, release := range l.changelog.Releases {
+		date, err := time.Parse("2006-01-02", release.Date)
+		if err != nil {
+			continue
+		}
+		if newer != nil && date.After(newerDate) {
+			l.report(l.releaseLine(i+1), "release %s is dated %s, after newer release %s dated %s", release.Name, release.Date, newer.Name, newer.Date)
+		}
+		newer, newerDate = &l.changelog.Releases[i], date
+	}
+}
+
+func checkVersionFormat(l *linter) {
+	for i, release := range l.changelog.Releases {
+		if _, err := ParseVersion(release.Name); err != nil {
+			l.report(l.releaseLine(i+1), "%v", err)
+		}
+	}
+}
+
+func checkVersionOrder(l *linter) {
+	var newer *Release
+	var newerVersion Version
+	for i, release := range l.changelog.Releases {
+		version, err := ParseVersion(release.Name)
+		if err != nil {
+			continue
+		}
+		if newer != nil && version.Compare(newerVersion) >= 0 && release.Name != newer.Name {
+			l.report(l.releaseLine(i+1), "version %s is not lower than newer version %s", release.Name, newer.Name)
+		}
+		newer, newerVersion = &l.changelog.Releases[i], version
+	}
+}
+
+func checkDuplicateVersion(l *linter) {
+	seen := map[string]bool{}
+	for i, release := range l.changelog.Releases {
+		if seen[release.Name] {
+			l.report(l.releaseLine(i+1), "version %s is released more than once", release.Name)
+		}
+		seen[release.Name] = true
+	}
+}
+
+func checkEmptyRelease(l *linter) {
+	for i, release := range l.changelog.Releases {
+		if len(sections(release)) == 0 {
+			l.report(l.releaseLine(i+1), "release %s has no entries", release.Name)
+		}
+	}
+}
+
+func checkEmptySection(l *linter) {
+	for i, release := range l.releases() {
+		for _, section := range release.Sections {
+			if len(section.Entries) == 0 {
+				l.report(l.sectionLine(i, section.Name), "section %s of release %s has no entries", section.Name, displayName(i, release))
+			}
+		}
+	}
+}
+
+func checkSectionName(l *linter) {
+	for i, release := range l.releases() {
+		for _, section := range release.Sections {
+			if standardSectionRank(section.Name) < 0 {
+				l.report(l.sectionLine(i, section.Name), "section %s of release %s is not one of %s", section.Name, displayName(i, release), strings.Join(StandardSections, ", "))
+			}
+		}
+	}
+}
+
+// compareLink returns the compare link of the release with the given name, or
+// nil if the changelog has none.
+func (l *linter) compareLink(name string) *linkNode {
+	for i, link := range l.changelog.document.links {
+		if link.compare && strings.EqualFold(link.title, name) {
+			return &l.changelog.document.links[i]
+		}
+	}
+
+	return nil
+}
+
+// expectedTargets returns the targets that the compare link of the release at
+// the given index should compare, or false if the release needs no compare
+// link because it is the first release.
+func (l *linter) expectedTargets(index int) (string, string, bool) {
+	if index == 0 {
+		return l.changelog.Tag(l.changelog.LatestRelease.Name), "HEAD", true
+	}
+	releases := l.changelog.Releases
+	if index >= len(releases) {
+		return "", "", false
+	}
+
+	return l.changelog.Tag(releases[index].Name), l.changelog.Tag(releases[index-1].Name), true
+}
+
+func checkCompareLink(l *linter) {
+	if l.changelog.document == nil {
+		return
+	}
+
+	for i, release := range l.releases() {
+		if _, _, ok := l.expectedTargets(i); !ok {
+			continue
+		}
+		if l.compareLink(displayName(i, release)) == nil {
+			l.report(l.releaseLine(i), "release %s has no compare link", displayName(i, release))
+		}
+	}
+}
+
+func checkLinkChain(l *linter) {
+	if l.changelog.document == nil {
+		return
+	}
+
+	for i, release := range l.releases() {
+		from, to, ok := l.expectedTargets(i)
+		link := l.compareLink(displayName(i, release))
+		if !ok || link == nil {
+			continue
+		}
+		if link.from != from || link.to != to {
+			l.report(link.number, "compare link of release %s compares %s...%s instead of %s...%s", displayName(i, release), link.from, link.to, from, to)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLintValidChangelogHasNoIssues(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/lint_valid.md")
+
+	issues, err := Lint(changelog)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, but was %v", issues)
+	}
+}
+
+func TestLint(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/lint.md")
+	expectedIssues := []LintIssue{
+		{"empty-section", 7, "section Added of release Unreleased has no entries"},
+		{"compare-link", 9, "release 1.1.0 has no compare link"},
+		{"section-name", 11, "section Improved of release 1.1.0 is not one of Added, Changed, Deprecated, Removed, Fixed, Security"},
+		{"date-format", 15, "date '2021-02-30' of release 1.2.0 is not an ISO 8601 date"},
+		{"version-order", 15, "version 1.2.0 is not lower than newer version 1.1.0"},
+		{"date-order", 21, "release 1.0.0 is dated 2021-03-02, after newer release 1.1.0 dated 2021-03-01"},
+		{"empty-release", 21, "release 1.0.0 has no entries"},
+		{"duplicate-version", 23, "version 1.0.0 is released more than once"},
+		{"version-format", 29, "'latest' is not a semantic version"},
+		{"link-chain", 36, "compare link of release 1.2.0 compares 1.0.0...1.1.0 instead of 1.0.0...1.2.0"},
+		{"link-chain", 37, "compare link of release 1.0.0 compares 1.0.0...1.0.0 instead of latest...1.0.0"},
+	}
+
+	issues, err := Lint(changelog)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if !reflect.DeepEqual(issues, expectedIssues) {
+		t.Errorf("expected issues to be\n%v\nbut was\n%v", expectedIssues, issues)
+	}
+}
+
+func TestLintSelectedRules(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/lint.md")
+
+	issues, err := Lint(changelog, "duplicate-version", "empty-release")
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if len(issues) != 2 || issues[0].Rule != "empty-release" || issues[1].Rule != "duplicate-version" {
+		t.Errorf("expected only issues of the selected rules, but was %v", issues)
+	}
+}
+
+func TestLintUnknownRuleReturnsError(t *testing.T) {
+	_, err := Lint(Changelog{}, "spelling")
+
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
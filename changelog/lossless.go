@@ -0,0 +1,608 @@
+package changelog
+
+import (
+	"io"
+	"strings"
+)
+
+// document is a concrete syntax tree of a parsed changelog. It keeps the
+// original lines of every part of the changelog together with a fingerprint of
+// how that part rendered when it was parsed, so that parts which have not been
+// edited since can be written back byte for byte.
+type document struct {
+	title       string
+	description string
+	preamble    []string
+	releases    []*releaseNode
+
+	// descriptionLines marks the lines of the preamble that the description
+	// was parsed from.
+	descriptionLines map[int]bool
+
+	links            []linkNode
+	linksFingerprint string
+
+	// lineEnding is the line ending of the changelog, and finalNewline whether
+	// its last line ends with one.
+	lineEnding   string
+	finalNewline bool
+}
+
+// releaseNode holds the original lines of a release. Lines following the last
+// section of a release belong to that section.
+type releaseNode struct {
+	name             string
+	line             int
+	fingerprint      string
+	titleFingerprint string
+
+	head     []string
+	sections []*sectionNode
+}
+
+// sectionNode holds the original lines of a section. Lines between two entries
+// belong to the first of them, lines following the last entry are the tail of
+// the section.
+type sectionNode struct {
+	name        string
+	line        int
+	fingerprint string
+
+	head    []string
+	entries []*entryNode
+	tail    []string
+}
+
+// entryNode holds the original lines of a single entry, including its
+// continuation lines and nested entries. The first size lines are the entry
+// itself, the lines after them lie between the entry and the next one.
+type entryNode struct {
+	fingerprint string
+	column      int
+	size        int
+	lines       []string
+}
+
+// linkNode is a single line of the link reference block at the end of a
+// changelog. The title is set for every link reference, the targets are only
+// set for compare links.
+type linkNode struct {
+	line    string
+	number  int
+	compare bool
+
+	title string
+	from  string
+	to    string
+}
+
+// newDocument builds the document of the given tokens, as parsed into the given
+// changelog.
+func newDocument(tokens []token, changelog Changelog) (*document, error) {
+	doc := &document{
+		title:        changelog.Title,
+		description:  changelog.Description,
+		lineEnding:   "\n",
+		finalNewline: true,
+	}
+	for _, token := range tokens {
+		if ending := token.pos().ending; ending != "" {
+			doc.lineEnding = ending
+			break
+		}
+	}
+	if len(tokens) > 0 {
+		doc.finalNewline = tokens[len(tokens)-1].pos().ending != ""
+	}
+
+	linksStart := len(tokens)
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if _, ok := tokens[i].(releaseTitle); ok {
+			break
+		}
+		if _, ok := tokens[i].(releaseCompareLink); ok {
+			linksStart = i
+		}
+	}
+
+	var release *releaseNode
+	var section *sectionNode
+	var pending []string
+	closeSection := func() {
+		if section != nil {
+			section.tail = append(section.tail, pending...)
+		}
+		pending = nil
+	}
+
+	for _, token := range tokens[:linksStart] {
+		line := token.pos().Text
+
+		switch token := token.(type) {
+		case releaseTitle:
+			closeSection()
+			section = nil
+			release = &releaseNode{name: token.Content, line: token.Line, head: []string{line}}
+			doc.releases = append(doc.releases, release)
+			continue
+		case sectionTitle:
+			if release != nil {
+				closeSection()
+				section = &sectionNode{name: token.Content, line: token.Line, head: []string{line}}
+				release.sections = append(release.sections, section)
+				continue
+			}
+		case changeEntry, entryContinuation:
+			if section != nil && len(section.entries) > 0 && len(pending) == 0 {
+				last := section.entries[len(section.entries)-1]
+				if token.pos().Column > last.column {
+					last.lines = append(last.lines, line)
+					last.size++
+					continue
+				}
+			}
+			if _, ok := token.(changeEntry); ok && section != nil {
+				if len(section.entries) > 0 {
+					last := section.entries[len(section.entries)-1]
+					last.lines = append(last.lines, pending...)
+				}
+				pending = nil
+				section.entries = append(section.entries, &entryNode{column: token.pos().Column, size: 1, lines: []string{line}})
+				continue
+			}
+		}
+
+		switch {
+		case release == nil:
+			if isDescriptionToken(token) {
+				if doc.descriptionLines == nil {
+					doc.descriptionLines = map[int]bool{}
+				}
+				doc.descriptionLines[len(doc.preamble)] = true
+			}
+			doc.preamble = append(doc.preamble, line)
+		case section == nil:
+			release.head = append(release.head, line)
+		case len(section.entries) == 0:
+			section.head = append(section.head, line)
+		default:
+			pending = append(pending, line)
+		}
+	}
+	closeSection()
+
+	// The empty lines between the paragraphs of the description are part of it.
+	last := -1
+	for i, line := range doc.preamble {
+		if !doc.descriptionLines[i] {
+			if strings.TrimSpace(line) != "" {
+				last = -1
+			}
+			continue
+		}
+		for j := last + 1; last >= 0 && j < i; j++ {
+			doc.descriptionLines[j] = true
+		}
+		last = i
+	}
+
+	for _, token := range tokens[linksStart:] {
+		link, ok := token.(releaseCompareLink)
+		node := linkNode{
+			line:    token.pos().Text,
+			number:  token.pos().Line,
+			compare: ok && link.ToTarget != "",
+		}
+		if ok {
+			node.title = link.Title
+		}
+		if node.compare {
+			node.from, node.to = link.FromTarget, link.ToTarget
+		}
+		doc.links = append(doc.links, node)
+	}
+
+	releases := append([]Release{changelog.Unreleased}, changelog.Releases...)
+	for i, node := range doc.releases {
+		if i >= len(releases) {
+			break
+		}
+		if err := node.fingerprintWith(releases[i], releaseTemplate(i)); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	doc.linksFingerprint, err = renderFragment("links", changelog)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// releaseTemplate returns the name of the template that renders the release at
+// the given index, where the unreleased changes come first.
+func releaseTemplate(index int) string {
+	if index == 0 {
+		return "unreleased"
+	}
+
+	return "release"
+}
+
+// findSection returns the section of the given release with the given name,
+// including sections without entries.
+func findSection(release Release, name string) (Section, bool) {
+	if section := release.Section(name); section != nil {
+		return *section, true
+	}
+
+	return Section{}, false
+}
+
+func (node *releaseNode) fingerprintWith(release Release, template string) error {
+	var err error
+	if node.fingerprint, err = renderFragment(template, release); err != nil {
+		return err
+	}
+	if node.titleFingerprint, err = renderFragment("release title", release); err != nil {
+		return err
+	}
+
+	for _, sectionNode := range node.sections {
+		section, ok := findSection(release, sectionNode.name)
+		if !ok {
+			continue
+		}
+		if sectionNode.fingerprint, err = renderFragment("section", section); err != nil {
+			return err
+		}
+
+		for i, entryNode := range sectionNode.entries {
+			if i >= len(section.Entries) {
+				break
+			}
+			if entryNode.fingerprint, err = renderFragment("entry", section.Entries[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (node *releaseNode) lines() []string {
+	lines := append([]string{}, node.head...)
+	for _, section := range node.sections {
+		lines = append(lines, section.lines()...)
+	}
+
+	return lines
+}
+
+func (node *sectionNode) lines() []string {
+	lines := append([]string{}, node.head...)
+	for _, entry := range node.entries {
+		lines = append(lines, entry.lines...)
+	}
+
+	return append(lines, node.tail...)
+}
+
+// renderLossless renders the given changelog using the original lines of the
+// document for every part that renders the same as when it was parsed.
+func renderLossless(changelog Changelog, doc *document, writer io.Writer) error {
+	out := strings.Builder{}
+
+	if changelog.Title == doc.title {
+		writeLines(&out, doc.preamble[:1])
+	} else {
+		title, err := renderFragment("title", changelog)
+		if err != nil {
+			return err
+		}
+		out.WriteString(title)
+	}
+
+	if changelog.Description == doc.description {
+		writeLines(&out, doc.preamble[1:])
+	} else {
+		writeLines(&out, doc.preambleWithDescription(changelog.Description))
+	}
+
+	used := make([]bool, len(doc.releases))
+	moved := false
+	releases := append([]Release{changelog.Unreleased}, changelog.Releases...)
+	for i, release := range releases {
+		node := doc.findRelease(i, release.Name, used)
+		if node == nil && i > 0 && !moved {
+			node, moved = doc.releasedNode(release, changelog.Unreleased)
+		}
+		if node == nil {
+			fragment, err := renderFragment(releaseTemplate(i), release)
+			if err != nil {
+				return err
+			}
+			separate(&out)
+			out.WriteString(fragment)
+			continue
+		}
+
+		if err := node.render(&out, release, releaseTemplate(i)); err != nil {
+			return err
+		}
+	}
+
+	links, err := renderFragment("links", changelog)
+	if err != nil {
+		return err
+	}
+	doc.renderLinks(&out, links)
+
+	_, err = io.WriteString(writer, doc.withLineEndings(out.String()))
+	return err
+}
+
+// withLineEndings returns the rendered changelog with the line endings of the
+// document, and without a final newline if the document had none.
+func (doc *document) withLineEndings(rendered string) string {
+	if doc.lineEnding != "\n" {
+		rendered = strings.ReplaceAll(rendered, "\n", doc.lineEnding)
+	}
+	if !doc.finalNewline {
+		rendered = strings.TrimSuffix(rendered, doc.lineEnding)
+	}
+
+	return rendered
+}
+
+// isDescriptionToken returns whether the description of a changelog is parsed
+// from the given token.
+func isDescriptionToken(t token) bool {
+	switch t.(type) {
+	case textLine, entryContinuation, changeEntry:
+		return true
+	}
+
+	return false
+}
+
+// preambleWithDescription returns the lines of the preamble following the
+// title, with the lines of the description replaced by the given description.
+// Other lines, such as link definitions, are kept. A blank line that would
+// follow another because description lines were removed is left out.
+func (doc *document) preambleWithDescription(description string) []string {
+	var descriptionLines []string
+	if description != "" {
+		descriptionLines = strings.Split(description, "\n")
+	}
+
+	lines := []string{}
+	replaced, removed := false, false
+	for i := 1; i < len(doc.preamble); i++ {
+		line := doc.preamble[i]
+		if !doc.descriptionLines[i] {
+			blank := strings.TrimSpace(line) == ""
+			if blank && removed && len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+				continue
+			}
+			lines = append(lines, line)
+			removed = false
+			continue
+		}
+
+		if !replaced && len(descriptionLines) > 0 {
+			lines = append(lines, descriptionLines...)
+		} else {
+			removed = true
+		}
+		replaced = true
+	}
+	if replaced || len(descriptionLines) == 0 {
+		return lines
+	}
+
+	// The preamble had no description, so the description is inserted after
+	// the blank lines following the title.
+	index := 0
+	for index < len(lines) && strings.TrimSpace(lines[index]) == "" {
+		index++
+	}
+	result := append([]string{}, lines[:index]...)
+	if index == 0 {
+		result = append(result, "")
+	}
+	result = append(result, descriptionLines...)
+	result = append(result, "")
+
+	return append(result, lines[index:]...)
+}
+
+// renderLinks writes the link reference block to out. When the compare links
+// have changed, they are replaced by the given links while any other link
+// references are kept, except for those with the same title as one of the given
+// links since only the first definition of a link reference is used. New links
+// are separated from the document before them by an empty line, since a link
+// reference cannot interrupt a paragraph.
+func (doc *document) renderLinks(out *strings.Builder, links string) {
+	unchanged := links == doc.linksFingerprint
+
+	titles := map[string]bool{}
+	for _, line := range strings.Split(links, "\n") {
+		if isReleaseCompareLink(line) {
+			titles[strings.ToLower(lexReleaseCompareLink(line).Title)] = true
+		}
+	}
+
+	written := false
+	for _, link := range doc.links {
+		if link.compare && link.line == "" && links == "" {
+			// The placeholder of a formatted document is left out when there
+			// are no compare links, with the blank line before it if nothing
+			// follows it.
+			if len(doc.links) == 1 {
+				trimmed := strings.TrimSuffix(out.String(), "\n")
+				out.Reset()
+				out.WriteString(trimmed)
+			}
+			continue
+		}
+		if unchanged || (!link.compare && !titles[strings.ToLower(link.title)]) {
+			writeLines(out, []string{link.line})
+			continue
+		}
+		if !written {
+			out.WriteString(links)
+			written = true
+		}
+	}
+	if !unchanged && !written && links != "" {
+		separate(out)
+		out.WriteString(links)
+	}
+}
+
+// releasedNode returns a node for the given new release that holds the
+// sections of the unreleased changes which were moved to it, so that their
+// original lines are kept when the unreleased changes are released. It returns
+// false if none of the sections of the unreleased changes were moved.
+func (doc *document) releasedNode(release Release, unreleased Release) (*releaseNode, bool) {
+	if len(doc.releases) == 0 {
+		return nil, false
+	}
+
+	// The title of the node has no fingerprint, so it is rendered anew.
+	node := &releaseNode{name: release.Name, head: []string{"", ""}}
+	for _, sectionNode := range doc.releases[0].sections {
+		if unreleased.Section(sectionNode.name) == nil && release.Section(sectionNode.name) != nil {
+			node.sections = append(node.sections, sectionNode)
+		}
+	}
+	if len(node.sections) == 0 {
+		return nil, false
+	}
+
+	return node, true
+}
+
+// findRelease returns the unused node of the release at the given index with
+// the given name, or nil if the release was not part of the document. The
+// unreleased changes are always the first node.
+func (doc *document) findRelease(index int, name string, used []bool) *releaseNode {
+	for i, node := range doc.releases {
+		if used[i] || (index == 0) != (i == 0) {
+			continue
+		}
+		if index == 0 || node.name == name {
+			used[i] = true
+			return node
+		}
+	}
+
+	return nil
+}
+
+func (node *releaseNode) render(out *strings.Builder, release Release, template string) error {
+	fingerprint, err := renderFragment(template, release)
+	if err != nil {
+		return err
+	}
+	if fingerprint == node.fingerprint {
+		writeLines(out, node.lines())
+		return nil
+	}
+
+	title, err := renderFragment("release title", release)
+	if err != nil {
+		return err
+	}
+	if template == "unreleased" || title == node.titleFingerprint {
+		writeLines(out, node.head)
+	} else {
+		out.WriteString(title)
+		writeLines(out, node.head[1:])
+	}
+
+	nodes := map[string]*sectionNode{}
+	for _, sectionNode := range node.sections {
+		nodes[sectionNode.name] = sectionNode
+	}
+
+	for _, section := range release.Sections {
+		if sectionNode, ok := nodes[section.Name]; ok {
+			if err := sectionNode.render(out, section); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(section.Entries) == 0 {
+			continue
+		}
+		fragment, err := renderFragment("section", section)
+		if err != nil {
+			return err
+		}
+		separate(out)
+		out.WriteString(fragment)
+	}
+
+	return nil
+}
+
+// render writes the section to out, keeping the original lines of the entries
+// that have not changed.
+func (node *sectionNode) render(out *strings.Builder, section Section) error {
+	fingerprint, err := renderFragment("section", section)
+	if err != nil {
+		return err
+	}
+	if fingerprint == node.fingerprint {
+		writeLines(out, node.lines())
+		return nil
+	}
+	if len(section.Entries) == 0 {
+		return nil
+	}
+
+	writeLines(out, node.head)
+	used := make([]bool, len(node.entries))
+	for _, entry := range section.Entries {
+		fragment, err := renderFragment("entry", entry)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for i, entryNode := range node.entries {
+			if !used[i] && entryNode.fingerprint == fragment {
+				used[i] = true
+				found = true
+				writeLines(out, entryNode.lines)
+				break
+			}
+		}
+		if !found {
+			out.WriteString(fragment)
+		}
+	}
+	writeLines(out, node.tail)
+
+	return nil
+}
+
+// separate writes an empty line to out unless the last line written to it is
+// empty already, so that a newly rendered fragment does not follow a title or
+// entry directly.
+func separate(out *strings.Builder) {
+	if written := out.String(); written != "" && !strings.HasSuffix(written, "\n\n") {
+		out.WriteString("\n")
+	}
+}
+
+func writeLines(out *strings.Builder, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+		out.WriteString("\n")
+	}
+}
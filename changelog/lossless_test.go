@@ -0,0 +1,358 @@
+package changelog
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func parseLosslessTestdata(t *testing.T, name string) Changelog {
+	t.Helper()
+
+	input, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := LexReader(strings.NewReader(string(input)))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog, err := Parse(tokens, Lossless())
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	return changelog
+}
+
+func assertRendersAs(t *testing.T, changelog Changelog, name string) {
+	t.Helper()
+
+	expectedOutput, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actualOutput := strings.Builder{}
+
+	if err := Render(changelog, &actualOutput); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	if actualOutput.String() != string(expectedOutput) {
+		t.Errorf("expected output to be\n%s\nbut was\n%s", expectedOutput, actualOutput.String())
+	}
+}
+
+func TestParseLosslessKeepsUnmodelledContent(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/lossless.md")
+
+	if len(changelog.Releases) != 2 {
+		t.Fatalf("expected 2 releases, but was %d", len(changelog.Releases))
+	}
+	if len(changelog.Releases[0].Added()) != 2 {
+		t.Errorf("expected 2 added entries, but was %d", len(changelog.Releases[0].Added()))
+	}
+	if changelog.URL != "https://github.com/mrombout/gochange" {
+		t.Errorf("expected URL to be parsed from the compare links, but was '%s'", changelog.URL)
+	}
+}
+
+func TestRenderLosslessWithoutChangesIsIdentical(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/lossless.md")
+
+	assertRendersAs(t, changelog, "testdata/lossless.md")
+}
+
+func TestRenderLosslessOnlyTouchesAddedEntries(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/lossless.md")
+
+	changelog.Unreleased.AddEntry(Added, Entry{Description: "Even more stuff."})
+	changelog.Unreleased.AddEntry(Fixed, Entry{Description: "A bug."})
+
+	assertRendersAs(t, changelog, "testdata/lossless_added.md")
+}
+
+func TestRenderLosslessRelease(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/lossless.md")
+
+	newRelease := changelog.Unreleased
+	newRelease.Name = "1.1.0"
+	newRelease.Date = "2019-01-05"
+	newRelease.PreviousRelease = &changelog.Releases[0]
+	changelog.Releases = append([]Release{newRelease}, changelog.Releases...)
+	changelog.LatestRelease = newRelease
+	changelog.Unreleased = Release{}
+
+	assertRendersAs(t, changelog, "testdata/lossless_released.md")
+}
+
+func TestRenderLosslessChangedDescription(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/lossless.md")
+	changelog.Description = "A new description."
+	actualOutput := strings.Builder{}
+
+	if err := Render(changelog, &actualOutput); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	if !strings.HasPrefix(actualOutput.String(), "# Changelog\n\nA new description.\n\n## [Unreleased]\n\n<!-- Add new entries below. -->\n") {
+		t.Errorf("expected description to be replaced, but was\n%s", actualOutput.String())
+	}
+}
+
+func TestRenderLosslessKeepsNestedEntries(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/nested.md")
+	changelog.Unreleased.AddEntry(Changed, Entry{
+		Description: "Another thing.",
+		Children:    []Entry{{Description: "With a detail."}},
+	})
+	actualOutput := strings.Builder{}
+
+	if err := Render(changelog, &actualOutput); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	if !strings.Contains(actualOutput.String(), "    It is no longer accepted on the command line.\n- Some things.\n- Another thing.\n  - With a detail.\n\n## [1.0.0]") {
+		t.Errorf("expected nested entry to be appended, but was\n%s", actualOutput.String())
+	}
+}
+
+func TestRenderLosslessInsertsNewSectionInOrder(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/lossless.md")
+	changelog.Releases[0].AddEntry(Deprecated, Entry{Description: "An option."})
+	actualOutput := strings.Builder{}
+
+	if err := Render(changelog, &actualOutput); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	if !strings.Contains(actualOutput.String(), "- Other stuff.\n\n### Deprecated\n\n- An option.\n\n### Removed\n") {
+		t.Errorf("expected section to be inserted before the removed section, but was\n%s", actualOutput.String())
+	}
+}
+
+func TestRenderLosslessChangedTitle(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/lossless.md")
+	changelog.Title = "Release notes"
+	actualOutput := strings.Builder{}
+
+	if err := Render(changelog, &actualOutput); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	if !strings.HasPrefix(actualOutput.String(), "# Release notes\n\n[![master]") {
+		t.Errorf("expected title to be replaced, but was\n%s", actualOutput.String())
+	}
+}
+
+func TestRenderLosslessKeepsLineEndings(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{"crlf", "# Changelog\r\n\r\nNotable changes.\r\n\r\n## [Unreleased]\r\n\r\n### Added\r\n\r\n- Invoices.\r\n"},
+		{"no final newline", "# Changelog\n\nNotable changes.\n\n## [Unreleased]\n\n### Added\n\n- Invoices."},
+		{"crlf without final newline", "# Changelog\r\n\r\n## [Unreleased]\r\n\r\n### Added\r\n\r\n- Invoices."},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tokens, err := LexReader(strings.NewReader(testCase.input))
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			changelog, err := Parse(tokens, Lossless())
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			output := strings.Builder{}
+
+			if err := Render(changelog, &output); err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+
+			if output.String() != testCase.input {
+				t.Errorf("expected output to be %q, but was %q", testCase.input, output.String())
+			}
+		})
+	}
+}
+
+func TestRenderLosslessWritesEditsWithLineEndings(t *testing.T) {
+	input := "# Changelog\r\n\r\n## [Unreleased]\r\n\r\n### Added\r\n\r\n- Invoices.\r\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog, err := Parse(tokens, Lossless())
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog.Unreleased.AddEntry(Added, Entry{Description: "Credit notes."})
+	output := strings.Builder{}
+
+	if err := Render(changelog, &output); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	expected := "# Changelog\r\n\r\n## [Unreleased]\r\n\r\n### Added\r\n\r\n- Invoices.\r\n- Credit notes.\r\n"
+	if output.String() != expected {
+		t.Errorf("expected output to be %q, but was %q", expected, output.String())
+	}
+}
+
+func TestRenderLosslessReplacesOnlyDescriptionLines(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		description string
+		expected    string
+	}{
+		{
+			"replaced",
+			"# Changelog\n\nNotable changes.\nOf gochange.\n\n[ci]: https://ci.example.com/gochange\n\n## [Unreleased]\n",
+			"All notable changes.",
+			"# Changelog\n\nAll notable changes.\n\n[ci]: https://ci.example.com/gochange\n\n## [Unreleased]\n",
+		},
+		{
+			"removed paragraph",
+			"# Changelog\n\nNotable changes.\n\nOf gochange.\n\n[ci]: https://ci.example.com/gochange\n\n## [Unreleased]\n",
+			"All notable changes.",
+			"# Changelog\n\nAll notable changes.\n\n[ci]: https://ci.example.com/gochange\n\n## [Unreleased]\n",
+		},
+		{
+			"added",
+			"# Changelog\n\n[ci]: https://ci.example.com/gochange\n\n## [Unreleased]\n",
+			"All notable changes.",
+			"# Changelog\n\nAll notable changes.\n\n[ci]: https://ci.example.com/gochange\n\n## [Unreleased]\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tokens, err := LexReader(strings.NewReader(testCase.input))
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			changelog, err := Parse(tokens, Lossless())
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			changelog.Description = testCase.description
+			output := strings.Builder{}
+
+			if err := Render(changelog, &output); err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+
+			if output.String() != testCase.expected {
+				t.Errorf("expected output to be %q, but was %q", testCase.expected, output.String())
+			}
+		})
+	}
+}
+
+func TestRenderLosslessReplacesUnrecognisedLinksWithTheSameTitle(t *testing.T) {
+	input := "# Changelog\n\n## [Unreleased]\n\n## [1.0.0] - 2019-01-05\n\n### Added\n\n- Invoices.\n\n[Unreleased]: https://git.example.com/p/diff?from=1.0.0&to=HEAD\n[kac]: https://keepachangelog.com/en/1.0.0/\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog, err := Parse(tokens, Lossless())
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog.URL = "https://github.com/mrombout/gochange"
+	output := strings.Builder{}
+
+	if err := Render(changelog, &output); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	expected := "[Unreleased]: https://github.com/mrombout/gochange/compare/1.0.0...HEAD\n[kac]: https://keepachangelog.com/en/1.0.0/\n"
+	if !strings.HasSuffix(output.String(), "- Invoices.\n\n"+expected) {
+		t.Errorf("expected links to be\n%s\nbut was\n%s", expected, output.String())
+	}
+}
+
+func TestRenderLosslessReleaseKeepsLinesOfUnreleasedSections(t *testing.T) {
+	input := "# Changelog\n\n## [Unreleased]\n\n### Added\n\n<!-- keep me -->\n\n* Invoices.\n\nThey are sent by mail.\n\n## [1.0.0] - 2019-01-05\n\n### Added\n\n- Credit notes.\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog, err := Parse(tokens, Lossless())
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	newRelease := changelog.Unreleased
+	newRelease.Name = "1.1.0"
+	newRelease.Date = "2019-02-01"
+	newRelease.PreviousRelease = &changelog.Releases[0]
+	changelog.Releases = append([]Release{newRelease}, changelog.Releases...)
+	changelog.LatestRelease = newRelease
+	changelog.Unreleased = Release{}
+	output := strings.Builder{}
+
+	if err := Render(changelog, &output); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	expected := "# Changelog\n\n## [Unreleased]\n\n## [1.1.0] - 2019-02-01\n\n### Added\n\n<!-- keep me -->\n\n* Invoices.\n\nThey are sent by mail.\n\n## [1.0.0] - 2019-01-05\n"
+	if !strings.HasPrefix(output.String(), expected) {
+		t.Errorf("expected output to start with\n%s\nbut was\n%s", expected, output.String())
+	}
+}
+
+func TestRenderLosslessSeparatesFirstSectionFromReleaseTitle(t *testing.T) {
+	input := "# Changelog\n\n## [Unreleased]\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog, err := Parse(tokens, Lossless())
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog.Unreleased.AddEntry(Added, Entry{Description: "Invoices."})
+	output := strings.Builder{}
+
+	if err := Render(changelog, &output); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	expected := "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Invoices.\n\n"
+	if output.String() != expected {
+		t.Errorf("expected output to be %q, but was %q", expected, output.String())
+	}
+	tokens, err = LexReader(strings.NewReader(output.String()))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if _, err := Parse(tokens); err != nil {
+		t.Errorf("expected output to parse, but was '%v'", err)
+	}
+}
+
+func TestRenderLosslessSeparatesNewLinksFromTheLastEntry(t *testing.T) {
+	input := "# Changelog\n\n## [Unreleased]\n\n## [1.0.0] - 2019-01-05\n\n### Added\n\n- First\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog, err := Parse(tokens, Lossless())
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	changelog.URL = "https://github.com/mrombout/gochange"
+	output := strings.Builder{}
+
+	if err := Render(changelog, &output); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	expected := "- First\n\n[Unreleased]: https://github.com/mrombout/gochange/compare/1.0.0...HEAD\n"
+	if !strings.HasSuffix(output.String(), expected) {
+		t.Errorf("expected output to end with %q, but was %q", expected, output.String())
+	}
+}
@@ -0,0 +1,298 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergeConflict describes the parts of a changelog that two sides of a merge
+// changed in different ways.
+type MergeConflict struct {
+	Conflicts []string
+}
+
+func (e *MergeConflict) Error() string {
+	return fmt.Sprintf("merge conflict in %s", strings.Join(e.Conflicts, ", "))
+}
+
+// Merge3 merges the changes that ours and theirs made to their common base.
+// Entries are merged per section and release, so that entries added by both
+// sides end up in the result, while entries removed by either side are left
+// out. A MergeConflict is returned when both sides changed the same entry,
+// release or description in different ways.
+//
+// The result is based on ours, so that a changelog that was parsed with the
+// Lossless option keeps its original lines wherever ours was not changed.
+func Merge3(base, ours, theirs Changelog) (Changelog, error) {
+	conflict := &MergeConflict{}
+	merge := func(name, base, ours, theirs string) string {
+		result, ok := mergeValue(base, ours, theirs)
+		if !ok {
+			conflict.Conflicts = append(conflict.Conflicts, name)
+		}
+		return result
+	}
+
+	result := ours
+	result.Title = merge("title", base.Title, ours.Title, theirs.Title)
+	result.Description = merge("description", base.Description, ours.Description, theirs.Description)
+	result.URL = merge("URL", base.URL, ours.URL, theirs.URL)
+	result.TagPrefix = merge("tag prefix", base.TagPrefix, ours.TagPrefix, theirs.TagPrefix)
+
+	result.Unreleased = mergeRelease(base.Unreleased, ours.Unreleased, theirs.Unreleased, "Unreleased", conflict)
+	result.Releases = mergeReleases(base.Releases, ours.Releases, theirs.Releases, conflict)
+	connectAllReleases(&result)
+	findAndSetLatestRelease(&result)
+
+	if len(conflict.Conflicts) > 0 {
+		return result, conflict
+	}
+
+	return result, nil
+}
+
+// mergeValue merges a single value, and returns false if both sides changed it
+// in different ways.
+func mergeValue(base, ours, theirs string) (string, bool) {
+	switch {
+	case ours == theirs || theirs == base:
+		return ours, true
+	case ours == base:
+		return theirs, true
+	}
+
+	return ours, false
+}
+
+// findReleaseByName returns the release with the given name, or false if there
+// is no such release.
+func findReleaseByName(releases []Release, name string) (Release, bool) {
+	for _, release := range releases {
+		if release.Name == name {
+			return release, true
+		}
+	}
+
+	return Release{}, false
+}
+
+// mergeReleases merges the releases of both sides. A release added by theirs
+// is inserted after the release that precedes it in theirs.
+func mergeReleases(base, ours, theirs []Release, conflict *MergeConflict) []Release {
+	result := []Release{}
+	for _, release := range ours {
+		baseRelease, inBase := findReleaseByName(base, release.Name)
+		theirRelease, inTheirs := findReleaseByName(theirs, release.Name)
+		switch {
+		case inTheirs:
+			result = append(result, mergeRelease(baseRelease, release, theirRelease, release.Name, conflict))
+		case !inBase:
+			result = append(result, release)
+		case !releaseEqual(baseRelease, release):
+			conflict.Conflicts = append(conflict.Conflicts, "release "+release.Name)
+			result = append(result, release)
+		}
+	}
+
+	for i, release := range theirs {
+		if _, inOurs := findReleaseByName(ours, release.Name); inOurs {
+			continue
+		}
+		if baseRelease, inBase := findReleaseByName(base, release.Name); inBase {
+			if !releaseEqual(baseRelease, release) {
+				conflict.Conflicts = append(conflict.Conflicts, "release "+release.Name)
+			}
+			continue
+		}
+
+		index := 0
+		if i > 0 {
+			for j := range result {
+				if result[j].Name == theirs[i-1].Name {
+					index = j + 1
+				}
+			}
+		}
+		result = append(result, Release{})
+		copy(result[index+1:], result[index:])
+		result[index] = release
+	}
+
+	return result
+}
+
+// mergeRelease merges the title and the entries of every section of a release
+// that both sides have.
+func mergeRelease(base, ours, theirs Release, name string, conflict *MergeConflict) Release {
+	result := ours
+	result.PreviousRelease = nil
+	result.Sections = append([]Section{}, ours.Sections...)
+
+	date, ok := mergeValue(base.Date, ours.Date, theirs.Date)
+	if !ok {
+		conflict.Conflicts = append(conflict.Conflicts, "date of release "+name)
+	}
+	result.Date = date
+	result.Yanked = ours.Yanked
+	if ours.Yanked == base.Yanked {
+		result.Yanked = theirs.Yanked
+	}
+
+	for i := range result.Sections {
+		section := &result.Sections[i]
+		entries, ok := mergeEntries(base.Entries(section.Name), section.Entries, theirs.Entries(section.Name))
+		if !ok {
+			conflict.Conflicts = append(conflict.Conflicts, fmt.Sprintf("%s section of release %s", section.Name, name))
+		}
+		section.Entries = entries
+	}
+
+	for _, section := range theirs.Sections {
+		if result.Section(section.Name) != nil {
+			continue
+		}
+		entries, ok := mergeEntries(base.Entries(section.Name), nil, section.Entries)
+		if !ok {
+			conflict.Conflicts = append(conflict.Conflicts, fmt.Sprintf("%s section of release %s", section.Name, name))
+		}
+		for _, entry := range entries {
+			result.AddEntry(section.Name, entry)
+		}
+	}
+
+	return result
+}
+
+// releaseEqual returns whether both releases have the same title and entries.
+func releaseEqual(a, b Release) bool {
+	if a.Name != b.Name || a.Date != b.Date || a.Yanked != b.Yanked {
+		return false
+	}
+
+	for _, release := range [][2]Release{{a, b}, {b, a}} {
+		for _, section := range release[0].Sections {
+			other := release[1].Entries(section.Name)
+			if len(section.Entries) != len(other) {
+				return false
+			}
+			for i := range section.Entries {
+				if !section.Entries[i].equal(other[i]) {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// matchEntries returns for every entry of side the index of the identical
+// entry of base, or -1 if side added the entry.
+func matchEntries(base, side []Entry) []int {
+	used := make([]bool, len(base))
+	matches := make([]int, len(side))
+	for i, entry := range side {
+		matches[i] = -1
+		for j := range base {
+			if !used[j] && base[j].equal(entry) {
+				used[j] = true
+				matches[i] = j
+				break
+			}
+		}
+	}
+
+	return matches
+}
+
+// additions groups the entries that side added by the index of the entry of
+// base they follow, which is -1 for entries added before any entry of base.
+func additions(side []Entry, matches []int) map[int][]Entry {
+	groups := map[int][]Entry{}
+	previous := -1
+	for i, entry := range side {
+		if matches[i] >= 0 {
+			previous = matches[i]
+			continue
+		}
+		groups[previous] = append(groups[previous], entry)
+	}
+
+	return groups
+}
+
+// kept returns which entries of base are still part of side.
+func kept(base []Entry, matches []int) []bool {
+	result := make([]bool, len(base))
+	for _, match := range matches {
+		if match >= 0 {
+			result[match] = true
+		}
+	}
+
+	return result
+}
+
+// mergeEntries merges the entries of a section. Entries are identified by
+// their description, so an edited entry is an entry that was removed and
+// replaced by another at the same position. It returns false if both sides
+// replaced the same entry by different entries.
+func mergeEntries(base, ours, theirs []Entry) ([]Entry, bool) {
+	ourMatches := matchEntries(base, ours)
+	theirMatches := matchEntries(base, theirs)
+	ourKept, theirKept := kept(base, ourMatches), kept(base, theirMatches)
+	ourAdditions, theirAdditions := additions(ours, ourMatches), additions(theirs, theirMatches)
+
+	ok := true
+	for i := range base {
+		if ourKept[i] || theirKept[i] {
+			continue
+		}
+		previous := i - 1
+		for previous >= 0 && !ourKept[previous] && !theirKept[previous] {
+			previous--
+		}
+		ourEdit, theirEdit := ourAdditions[previous], theirAdditions[previous]
+		if len(ourEdit) > 0 && len(theirEdit) > 0 && !ourEdit[0].equal(theirEdit[0]) {
+			ok = false
+		}
+	}
+
+	// Entries that theirs removed stay in place until all additions of theirs
+	// are inserted, so that they can still be positioned after them.
+	result := append([]Entry{}, ours...)
+	matches := append([]int{}, ourMatches...)
+	for previous := -1; previous < len(base); previous++ {
+		for _, entry := range theirAdditions[previous] {
+			if (Section{Entries: result}).contains(entry) {
+				continue
+			}
+
+			index := 0
+			for j, match := range matches {
+				if match >= 0 && match <= previous {
+					index = j + 1
+				}
+			}
+			for index < len(matches) && matches[index] < 0 {
+				index++
+			}
+
+			result = append(result, Entry{})
+			copy(result[index+1:], result[index:])
+			result[index] = entry
+			matches = append(matches, 0)
+			copy(matches[index+1:], matches[index:])
+			matches[index] = -1
+		}
+	}
+
+	merged := []Entry{}
+	for i, entry := range result {
+		if matches[i] < 0 || theirKept[matches[i]] {
+			merged = append(merged, entry)
+		}
+	}
+
+	return merged, ok
+}
@@ -0,0 +1,137 @@
+package changelog
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func entries(descriptions ...string) []Entry {
+	result := []Entry{}
+	for _, description := range descriptions {
+		result = append(result, Entry{Description: description})
+	}
+
+	return result
+}
+
+func TestMergeEntries(t *testing.T) {
+	testCases := []struct {
+		name            string
+		base            []Entry
+		ours            []Entry
+		theirs          []Entry
+		expectedEntries []Entry
+	}{
+		{"both append", entries("A"), entries("A", "B"), entries("A", "C"), entries("A", "B", "C")},
+		{"both append the same", entries("A"), entries("A", "B"), entries("A", "B"), entries("A", "B")},
+		{"theirs prepend", entries("A"), entries("A", "B"), entries("C", "A"), entries("C", "A", "B")},
+		{"theirs remove", entries("A", "B"), entries("A", "B", "C"), entries("B"), entries("B", "C")},
+		{"ours remove", entries("A", "B"), entries("B"), entries("A", "B", "C"), entries("B", "C")},
+		{"theirs edit", entries("A", "B"), entries("A", "B", "C"), entries("A", "B2"), entries("A", "B2", "C")},
+		{"both edit the same", entries("A", "B"), entries("A", "B2"), entries("A", "B2"), entries("A", "B2")},
+		{"empty base", nil, entries("A"), entries("B"), entries("A", "B")},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, ok := mergeEntries(testCase.base, testCase.ours, testCase.theirs)
+
+			if !ok {
+				t.Fatalf("expected no conflict, but there was one")
+			}
+			if !reflect.DeepEqual(result, testCase.expectedEntries) {
+				t.Errorf("expected entries to be %v, but was %v", testCase.expectedEntries, result)
+			}
+		})
+	}
+}
+
+func TestMergeEntriesWhenBothEditTheSameEntryConflicts(t *testing.T) {
+	_, ok := mergeEntries(entries("A", "B"), entries("A", "B2"), entries("A", "B3"))
+
+	if ok {
+		t.Errorf("expected a conflict, but there was none")
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	base := Changelog{
+		Description: "Notable changes.",
+		Unreleased:  Release{Sections: []Section{{Name: Added, Entries: entries("A")}}},
+		Releases:    []Release{{Name: "1.0.0", Date: "2021-01-01", Sections: []Section{{Name: Fixed, Entries: entries("F")}}}},
+	}
+	ours := Changelog{
+		Description: "Notable changes.",
+		Unreleased:  Release{Sections: []Section{{Name: Added, Entries: entries("A", "B")}}},
+		Releases:    []Release{{Name: "1.0.0", Date: "2021-01-01", Yanked: true, Sections: []Section{{Name: Fixed, Entries: entries("F")}}}},
+	}
+	theirs := Changelog{
+		Description: "All notable changes.",
+		Unreleased: Release{Sections: []Section{
+			{Name: Added, Entries: entries("A", "C")},
+			{Name: Fixed, Entries: entries("D")},
+		}},
+		Releases: []Release{
+			{Name: "1.0.0", Date: "2021-01-01", Sections: []Section{{Name: Fixed, Entries: entries("F")}}},
+			{Name: "0.9.0", Date: "2020-01-01"},
+		},
+	}
+
+	result, err := Merge3(base, ours, theirs)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if result.Description != "All notable changes." {
+		t.Errorf("expected description of theirs, but was '%s'", result.Description)
+	}
+	expectedSections := []Section{{Name: Added, Entries: entries("A", "B", "C")}, {Name: Fixed, Entries: entries("D")}}
+	if !reflect.DeepEqual(result.Unreleased.Sections, expectedSections) {
+		t.Errorf("expected unreleased sections to be %v, but was %v", expectedSections, result.Unreleased.Sections)
+	}
+	if len(result.Releases) != 2 || result.Releases[1].Name != "0.9.0" {
+		t.Fatalf("expected release 0.9.0 of theirs to be added, but releases were %v", result.Releases)
+	}
+	if !result.Releases[0].Yanked {
+		t.Errorf("expected release 1.0.0 to stay yanked, but it wasn't")
+	}
+	if result.Releases[0].PreviousRelease == nil || result.Releases[0].PreviousRelease.Name != "0.9.0" {
+		t.Errorf("expected releases to be connected, but previous release was %v", result.Releases[0].PreviousRelease)
+	}
+	if result.LatestRelease.Name != "1.0.0" {
+		t.Errorf("expected latest release to be '1.0.0', but was '%s'", result.LatestRelease.Name)
+	}
+}
+
+func TestMerge3WhenBothEditTheSameEntryReturnsConflict(t *testing.T) {
+	base := Changelog{Unreleased: Release{Sections: []Section{{Name: Added, Entries: entries("A")}}}}
+	ours := Changelog{Unreleased: Release{Sections: []Section{{Name: Added, Entries: entries("A2")}}}}
+	theirs := Changelog{Unreleased: Release{Sections: []Section{{Name: Added, Entries: entries("A3")}}}}
+
+	_, err := Merge3(base, ours, theirs)
+
+	var conflict *MergeConflict
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected a merge conflict, but was '%v'", err)
+	}
+	if !strings.Contains(err.Error(), "Added section of release Unreleased") {
+		t.Errorf("expected conflict in the added section, but was '%v'", err)
+	}
+}
+
+func TestMerge3KeepsOriginalLinesOfOurs(t *testing.T) {
+	base := parseLosslessTestdata(t, "testdata/lossless.md")
+	ours := parseLosslessTestdata(t, "testdata/lossless.md")
+	theirs := parseLosslessTestdata(t, "testdata/lossless.md")
+	ours.Unreleased.AddEntry(Added, Entry{Description: "Even more stuff."})
+	theirs.Unreleased.AddEntry(Fixed, Entry{Description: "A bug."})
+
+	result, err := Merge3(base, ours, theirs)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	assertRendersAs(t, result, "testdata/lossless_added.md")
+}
@@ -1,12 +1,66 @@
 package changelog
 
 import (
-	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 )
 
+// ParseError describes a token that did not appear where the parser expected
+// another kind of token. The column is only reported for indented tokens.
+type ParseError struct {
+	Line     int
+	Column   int
+	Expected string
+	Found    string
+}
+
+func (e *ParseError) Error() string {
+	if e.Column > 1 {
+		return fmt.Sprintf("%d:%d: expected %s, found %s", e.Line, e.Column, e.Expected, e.Found)
+	}
+	return fmt.Sprintf("%d: expected %s, found %s", e.Line, e.Expected, e.Found)
+}
+
+// ParseOption configures how Parse treats its input.
+type ParseOption func(stack *tokenStack)
+
+// Lossless makes Parse tolerate content it does not model, such as extra
+// paragraphs, HTML comments and custom link references, and keep the original
+// lines of the changelog so that Render only touches the parts that were
+// edited.
+func Lossless() ParseOption {
+	return func(stack *tokenStack) {
+		stack.lossless = true
+	}
+}
+
+// WithLinkProvider makes Parse recognise the compare links of the given link
+// provider besides those of the known forges, and use it for a changelog that
+// has no compare links.
+func WithLinkProvider(provider LinkProvider) ParseOption {
+	return func(stack *tokenStack) {
+		stack.links = provider
+	}
+}
+
 type tokenStack struct {
 	tokens []token
+
+	// eof is the position just past the last token, used to report errors at
+	// the end of the input.
+	eof position
+
+	// lossless indicates whether unmodelled content is skipped instead of
+	// rejected.
+	lossless bool
+
+	// links is the link provider to recognise compare links with.
+	links LinkProvider
+
+	// breakingMarkers are the markers of breaking changes besides the default
+	// ones.
+	breakingMarkers []string
 }
 
 func (t *tokenStack) peek() *token {
@@ -28,17 +82,30 @@ func (t *tokenStack) pop() *token {
 }
 
 // Parse parses a list of tokens as returned by `changelog.Lex`.
-func Parse(tokens []token) (Changelog, error) {
+func Parse(tokens []token, options ...ParseOption) (Changelog, error) {
 	changelog := newChangelog()
 
 	stack := tokenStack{
 		tokens: tokens,
 	}
+	for _, option := range options {
+		option(&stack)
+	}
+	if stack.links != nil {
+		tokens = resolveLinks(tokens, stack.links)
+		stack.tokens = tokens
+	}
+	if len(tokens) > 0 {
+		last := tokens[len(tokens)-1].pos()
+		stack.eof = position{Line: last.Line + 1, Column: 1}
+	}
 
-	if err := parseHeader(&stack); err != nil {
+	if err := parseHeader(&stack, &changelog); err != nil {
+		return changelog, err
+	}
+	if err := parseDescription(&stack, &changelog); err != nil {
 		return changelog, err
 	}
-	parseDescription(&stack, &changelog)
 	if err := parseUnreleased(&stack, &changelog); err != nil {
 		return changelog, err
 	}
@@ -48,32 +115,64 @@ func Parse(tokens []token) (Changelog, error) {
 
 	connectAllReleases(&changelog)
 	findAndSetLatestRelease(&changelog)
+	parseLinks(tokens, &changelog)
+	if changelog.Links == nil {
+		changelog.Links = stack.links
+	}
+
+	if stack.lossless {
+		document, err := newDocument(tokens, changelog)
+		if err != nil {
+			return changelog, err
+		}
+		changelog.document = document
+	}
 
 	return changelog, nil
 }
 
-func parseHeader(stack *tokenStack) error {
-	if _, err := acceptToken(stack, header1Title{}); err != nil {
-		return err
-	}
-	if _, err := acceptToken(stack, emptyLine{}); err != nil {
+func parseHeader(stack *tokenStack, changelog *Changelog) error {
+	title, err := acceptToken(stack, header1Title{})
+	if err != nil {
 		return err
 	}
+	changelog.Title = (*title).(header1Title).Content
 
-	return nil
+	return acceptEmptyLine(stack)
 }
 
-func parseDescription(stack *tokenStack, changelog *Changelog) {
-	for token := stack.peek(); !isToken(stack, releaseTitle{}); token = stack.peek() {
-		if val, ok := (*token).(textLine); ok {
-			changelog.Description += val.Content + "\n"
-		} else if _, ok := (*token).(emptyLine); ok {
-
+// parseDescription parses the lines up to the first release title as the
+// description of the changelog. The empty lines between its paragraphs are kept,
+// while other lines, such as link references, are left out of it.
+func parseDescription(stack *tokenStack, changelog *Changelog) error {
+	emptyLines := 0
+	for !isToken(stack, releaseTitle{}) {
+		token := stack.pop()
+		if token == nil {
+			return newParseError(stack, releaseTitle{})
 		}
 
-		stack.pop()
+		if !isDescriptionToken(*token) {
+			emptyLines = 0
+			if _, ok := (*token).(emptyLine); ok && changelog.Description != "" {
+				emptyLines = 1
+			}
+			continue
+		}
+		changelog.Description += strings.Repeat("\n", emptyLines)
+		emptyLines = 0
+		switch val := (*token).(type) {
+		case textLine:
+			changelog.Description += val.Content + "\n"
+		case entryContinuation, changeEntry:
+			changelog.Description += val.pos().Text + "\n"
+		}
+	}
+	if len(changelog.Description) > 0 {
+		changelog.Description = changelog.Description[:len(changelog.Description)-1]
 	}
-	changelog.Description = changelog.Description[:len(changelog.Description)-1]
+
+	return nil
 }
 
 func parseUnreleased(stack *tokenStack, changelog *Changelog) error {
@@ -83,6 +182,7 @@ func parseUnreleased(stack *tokenStack, changelog *Changelog) error {
 	if isToken(stack, emptyLine{}) {
 		acceptToken(stack, emptyLine{})
 	}
+	skipUnmodelled(stack)
 
 	currentRelease := Release{
 		Name: "Unreleased",
@@ -100,17 +200,21 @@ func parseUnreleased(stack *tokenStack, changelog *Changelog) error {
 func parseReleases(stack *tokenStack, changelog *Changelog) error {
 	for isToken(stack, releaseTitle{}) {
 		currentReleaseTitle, _ := acceptToken(stack, releaseTitle{})
-		if _, err := acceptToken(stack, emptyLine{}); err != nil {
+		if err := acceptEmptyLine(stack); err != nil {
 			return err
 		}
+		skipUnmodelled(stack)
 
 		if currentReleaseTitle, ok := (*currentReleaseTitle).(releaseTitle); ok {
 			currentRelease := Release{
-				Name: currentReleaseTitle.Content,
-				Date: currentReleaseTitle.Date,
+				Name:   currentReleaseTitle.Content,
+				Date:   currentReleaseTitle.Date,
+				Yanked: currentReleaseTitle.Yanked,
 			}
 
-			parseReleaseSections(stack, changelog, &currentRelease)
+			if err := parseReleaseSections(stack, changelog, &currentRelease); err != nil {
+				return err
+			}
 
 			changelog.Releases = append(changelog.Releases, currentRelease)
 		}
@@ -122,37 +226,32 @@ func parseReleases(stack *tokenStack, changelog *Changelog) error {
 func parseReleaseSections(stack *tokenStack, changelog *Changelog, release *Release) error {
 	for isToken(stack, sectionTitle{}) {
 		sectionToken, _ := acceptToken(stack, sectionTitle{})
-		if _, err := acceptToken(stack, emptyLine{}); err != nil {
+		if err := acceptEmptyLine(stack); err != nil {
 			return err
 		}
 
-		var list *[]Entry
 		if currentSectionToken, ok := (*sectionToken).(sectionTitle); ok {
-			switch currentSectionToken.Content {
-			case "Added":
-				list = &release.Added
-			case "Changed":
-				list = &release.Changed
-			case "Deprecated":
-				list = &release.Deprecated
-			case "Removed":
-				list = &release.Removed
-			case "Fixed":
-				list = &release.Fixed
-			case "Security":
-				list = &release.Security
+			section := release.Section(currentSectionToken.Content)
+			if section == nil {
+				release.Sections = append(release.Sections, Section{Name: currentSectionToken.Content})
+				section = &release.Sections[len(release.Sections)-1]
+			}
+			list := &section.Entries
+
+			if stack.lossless {
+				for skipUnmodelled(stack); isToken(stack, changeEntry{}); skipUnmodelled(stack) {
+					entry, _ := parseEntry(stack)
+					*list = append(*list, entry)
+				}
+				continue
 			}
 
 			for !isToken(stack, emptyLine{}) && len(stack.tokens) > 0 {
-				changeEntryToken, err := acceptToken(stack, changeEntry{})
+				entry, err := parseEntry(stack)
 				if err != nil {
 					return err
 				}
-				if val, ok := (*changeEntryToken).(changeEntry); ok {
-					*list = append(*list, Entry{
-						Description: val.Content,
-					})
-				}
+				*list = append(*list, entry)
 			}
 
 			if len(stack.tokens) > 0 {
@@ -164,6 +263,112 @@ func parseReleaseSections(stack *tokenStack, changelog *Changelog, release *Rele
 	return nil
 }
 
+// parseEntry parses a change entry together with its continuation lines and the
+// entries nested below it.
+func parseEntry(stack *tokenStack) (Entry, error) {
+	changeEntryToken, err := acceptToken(stack, changeEntry{})
+	if err != nil {
+		return Entry{}, err
+	}
+	val := (*changeEntryToken).(changeEntry)
+
+	entry := Entry{
+		Description: val.Content,
+		Breaking:    isBreaking(val.Content, stack.breakingMarkers),
+	}
+
+	for isNestedToken(stack, val) {
+		if continuationToken, ok := (*stack.peek()).(entryContinuation); ok {
+			stack.pop()
+			entry.Description += "\n" + continuationToken.Content
+			continue
+		}
+
+		child, err := parseEntry(stack)
+		if err != nil {
+			return entry, err
+		}
+		entry.Children = append(entry.Children, child)
+	}
+	entry.References = parseReferences(entry.Description)
+	entry.Authors = parseAuthors(entry.Description)
+
+	return entry, nil
+}
+
+// NewEntry returns the entry with the given description as Parse would parse
+// it, recognising the given breaking markers besides the default ones.
+func NewEntry(description string, breakingMarkers ...string) Entry {
+	markers := []string{}
+	for _, marker := range breakingMarkers {
+		markers = append(markers, strings.TrimSpace(marker))
+	}
+
+	return Entry{
+		Description: description,
+		Breaking:    isBreaking(description, markers),
+		References:  parseReferences(description),
+		Authors:     parseAuthors(description),
+	}
+}
+
+// isNestedToken returns whether the next token is a continuation line or an
+// entry that is indented further than the given entry.
+func isNestedToken(stack *tokenStack, parent changeEntry) bool {
+	if !isToken(stack, entryContinuation{}) && !isToken(stack, changeEntry{}) {
+		return false
+	}
+
+	return (*stack.peek()).pos().Column > parent.Column
+}
+
+// parseLinks sets the URL and link provider of the changelog from the compare
+// link of the unreleased changes, and the tag prefix from the first compare
+// link whose tag differs from the release it links to. The URL is left empty
+// when the link of the unreleased changes is not recognised, so that no compare
+// links are created for a repository that is not known.
+func parseLinks(tokens []token, changelog *Changelog) {
+	prefixFound := false
+	for _, token := range tokens {
+		link, ok := token.(releaseCompareLink)
+		if !ok || link.provider == nil {
+			continue
+		}
+
+		tag, name := link.ToTarget, link.Title
+		if strings.EqualFold(link.Title, "Unreleased") {
+			changelog.URL = link.Repository
+			changelog.Links = link.provider
+			tag, name = link.FromTarget, changelog.LatestRelease.Name
+		}
+
+		if !prefixFound && name != "HEAD" && strings.HasSuffix(tag, name) {
+			changelog.TagPrefix = strings.TrimSuffix(tag, name)
+			prefixFound = true
+		}
+	}
+}
+
+// resolveLinks returns the tokens with the compare links recognised by the
+// given link provider.
+func resolveLinks(tokens []token, provider LinkProvider) []token {
+	resolved := make([]token, len(tokens))
+	for i, token := range tokens {
+		if link, ok := token.(releaseCompareLink); ok {
+			if repository, from, to, ok := provider.ParseCompareURL(link.URL); ok {
+				link.provider = provider
+				link.Repository = repository
+				link.FromTarget = from
+				link.ToTarget = to
+				token = link
+			}
+		}
+		resolved[i] = token
+	}
+
+	return resolved
+}
+
 func connectAllReleases(changelog *Changelog) {
 	for index := range changelog.Releases {
 		if index < len(changelog.Releases)-1 {
@@ -172,20 +377,64 @@ func connectAllReleases(changelog *Changelog) {
 	}
 }
 
+// findAndSetLatestRelease sets the latest release of the changelog, which is
+// the first release since releases are listed newest first.
 func findAndSetLatestRelease(changelog *Changelog) {
 	if len(changelog.Releases) > 0 {
-		changelog.LatestRelease = changelog.Releases[len(changelog.Releases)-1]
+		changelog.LatestRelease = changelog.Releases[0]
 	}
 }
 
 func acceptToken(stack *tokenStack, tokenType interface{}) (*token, error) {
 	if !isToken(stack, tokenType) {
-		return nil, errors.New("unexpected token")
+		return nil, newParseError(stack, tokenType)
 	}
 
 	return stack.pop(), nil
 }
 
+// newParseError returns a ParseError for finding the next token on the stack
+// where a token of the given type was expected.
+func newParseError(stack *tokenStack, expectedType interface{}) *ParseError {
+	pos := stack.eof
+	var found interface{}
+	if token := stack.peek(); token != nil {
+		pos = (*token).pos()
+		found = *token
+	}
+
+	return &ParseError{
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Expected: describeToken(expectedType),
+		Found:    describeToken(found),
+	}
+}
+
+// acceptEmptyLine accepts the empty line that must follow a title. The empty
+// line is optional when parsing losslessly.
+func acceptEmptyLine(stack *tokenStack) error {
+	if stack.lossless && !isToken(stack, emptyLine{}) {
+		return nil
+	}
+
+	_, err := acceptToken(stack, emptyLine{})
+	return err
+}
+
+// skipUnmodelled skips over any content that is not part of the changelog
+// model, when parsing losslessly. The skipped content is preserved by the
+// document of the changelog instead.
+func skipUnmodelled(stack *tokenStack) {
+	if !stack.lossless {
+		return
+	}
+
+	for isToken(stack, textLine{}) || isToken(stack, emptyLine{}) || isToken(stack, entryContinuation{}) || isToken(stack, header1Title{}) || isToken(stack, releaseCompareLink{}) {
+		stack.pop()
+	}
+}
+
 func isToken(stack *tokenStack, tokenType interface{}) bool {
 	if len(stack.tokens) <= 0 {
 		return false
@@ -2,6 +2,8 @@ package changelog
 
 import (
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -68,11 +70,16 @@ func TestParseHeaderWhenValidNoError(t *testing.T) {
 		tokens: []token{header1Title{Content: "Changelog"}, emptyLine{}},
 	}
 
-	err := parseHeader(&tokenStack)
+	changelog := Changelog{}
+
+	err := parseHeader(&tokenStack, &changelog)
 
 	if err != nil {
 		t.Errorf("expected error to be nil, but was %v", err)
 	}
+	if changelog.Title != "Changelog" {
+		t.Errorf("expected title to be 'Changelog', but was '%s'", changelog.Title)
+	}
 }
 
 func TestParseHeaderWhenInvalidReturnsError(t *testing.T) {
@@ -81,8 +88,8 @@ func TestParseHeaderWhenInvalidReturnsError(t *testing.T) {
 		tokens        []token
 		expectedError error
 	}{
-		{"no header 1 title", []token{textLine{}, emptyLine{}}, errors.New("unexpected token")},
-		{"no empty line after header 1", []token{header1Title{}, textLine{}}, errors.New("unexpected token")},
+		{"no header 1 title", []token{textLine{}, emptyLine{}}, errors.New("0: expected changelog title, found text line")},
+		{"no empty line after header 1", []token{header1Title{}, textLine{}}, errors.New("0: expected empty line, found text line")},
 	}
 
 	for _, testCase := range testCases {
@@ -91,7 +98,7 @@ func TestParseHeaderWhenInvalidReturnsError(t *testing.T) {
 				tokens: testCase.tokens,
 			}
 
-			err := parseHeader(&tokenStack)
+			err := parseHeader(&tokenStack, &Changelog{})
 
 			if err.Error() != testCase.expectedError.Error() {
 				t.Errorf("expected error to be '%v', but was '%v'", testCase.expectedError.Error(), err.Error())
@@ -115,6 +122,21 @@ func TestParseDescription(t *testing.T) {
 	}
 }
 
+func TestParseDescriptionKeepsParagraphs(t *testing.T) {
+	expectedDescription := "Lorum ipsum.\n\nDolor sit amet."
+
+	changelog := Changelog{}
+	tokenStack := tokenStack{
+		tokens: []token{textLine{Content: "Lorum ipsum."}, emptyLine{}, emptyLine{}, textLine{Content: "Dolor sit amet."}, emptyLine{}, releaseTitle{}},
+	}
+
+	parseDescription(&tokenStack, &changelog)
+
+	if changelog.Description != expectedDescription {
+		t.Errorf("expected parser to have populated .Description with '%v', but was '%v'", expectedDescription, changelog.Description)
+	}
+}
+
 func TestParseReleaseSections(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -152,7 +174,7 @@ func TestParseReleaseSections(t *testing.T) {
 }
 
 func TestParseReleaseSectionsMissingEmptyLineAfterActionTitleReturnsError(t *testing.T) {
-	expectedError := errors.New("unexpected token")
+	expectedError := errors.New("0: expected empty line, found end of file")
 	testCases := []struct {
 		name       string
 		tokenStack []token
@@ -183,7 +205,7 @@ func TestParseReleaseSectionsMissingEmptyLineAfterActionTitleReturnsError(t *tes
 }
 
 func TestParseReleaseSectionsNotAChangeSectionAfterSectionTitleReturnsError(t *testing.T) {
-	expectedError := errors.New("unexpected token")
+	expectedError := errors.New("0: expected change entry, found text line")
 	testCases := []struct {
 		name       string
 		tokenStack []token
@@ -213,6 +235,76 @@ func TestParseReleaseSectionsNotAChangeSectionAfterSectionTitleReturnsError(t *t
 	}
 }
 
+func TestParseReleaseSectionsKeepsCustomSectionsInOrder(t *testing.T) {
+	tokenStack := tokenStack{
+		tokens: []token{
+			sectionTitle{Content: "Performance"},
+			emptyLine{},
+			changeEntry{Content: "Faster parsing."},
+			emptyLine{},
+			sectionTitle{Content: "Added"},
+			emptyLine{},
+			changeEntry{Content: "A feature."},
+			emptyLine{},
+			sectionTitle{Content: "Documentation"},
+			emptyLine{},
+			changeEntry{Content: "A guide."},
+			emptyLine{},
+		},
+	}
+	expectedSections := []Section{
+		{Name: "Performance", Entries: []Entry{{Description: "Faster parsing."}}},
+		{Name: "Added", Entries: []Entry{{Description: "A feature."}}},
+		{Name: "Documentation", Entries: []Entry{{Description: "A guide."}}},
+	}
+	release := Release{}
+
+	err := parseReleaseSections(&tokenStack, &Changelog{}, &release)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err.Error())
+	}
+	if !reflect.DeepEqual(release.Sections, expectedSections) {
+		t.Errorf("expected sections to be %v, but was %v", expectedSections, release.Sections)
+	}
+}
+
+func TestParseReleaseSectionsWithNestedEntries(t *testing.T) {
+	tokenStack := tokenStack{
+		tokens: []token{
+			sectionTitle{Content: "Changed"},
+			emptyLine{},
+			changeEntry{position: position{Column: 1}, Content: "Renamed a command, to migrate:"},
+			changeEntry{position: position{Column: 3}, Content: "Replace it in your scripts"},
+			entryContinuation{position: position{Column: 5}, Content: "and your aliases."},
+			changeEntry{position: position{Column: 3}, Content: "Move a flag."},
+			changeEntry{position: position{Column: 1}, Content: "Changed a thing"},
+			entryContinuation{position: position{Column: 3}, Content: "over two lines."},
+			emptyLine{},
+		},
+	}
+	expectedEntries := []Entry{
+		{
+			Description: "Renamed a command, to migrate:",
+			Children: []Entry{
+				{Description: "Replace it in your scripts\nand your aliases."},
+				{Description: "Move a flag."},
+			},
+		},
+		{Description: "Changed a thing\nover two lines."},
+	}
+	release := Release{}
+
+	err := parseReleaseSections(&tokenStack, &Changelog{}, &release)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err.Error())
+	}
+	if !reflect.DeepEqual(release.Changed(), expectedEntries) {
+		t.Errorf("expected entries to be %v, but was %v", expectedEntries, release.Changed())
+	}
+}
+
 func TestConnectAllRelease(t *testing.T) {
 	changelog := newChangelog()
 	changelog.Releases = append(changelog.Releases, Release{
@@ -249,7 +341,7 @@ func TestAcceptTokenReturnsErrorWhenTokenIsUnexpected(t *testing.T) {
 }
 
 func TestParseUnreleasedWhenDoesNotStartWithReleaseTitleThrowsError(t *testing.T) {
-	expectedError := errors.New("unexpected token")
+	expectedError := errors.New("0: expected release title, found empty line")
 	tokenStack := tokenStack{
 		tokens: []token{
 			emptyLine{},
@@ -310,7 +402,7 @@ func TestParseUnreleasedPopulatesUnreleased(t *testing.T) {
 }
 
 func TestParseReleasesNoEmptyLineAfterTitleReturnsError(t *testing.T) {
-	expectedError := errors.New("unexpected token")
+	expectedError := errors.New("0: expected empty line, found section title")
 	tokenStack := tokenStack{
 		tokens: []token{
 			releaseTitle{Content: "v1.0.0"},
@@ -343,6 +435,25 @@ func TestParseReleases(t *testing.T) {
 	// TODO: Assertions
 }
 
+func TestParseReleasesYanked(t *testing.T) {
+	tokenStack := tokenStack{
+		tokens: []token{
+			releaseTitle{Content: "0.0.5", Date: "2014-12-13", Yanked: true},
+			emptyLine{},
+		},
+	}
+	changelog := Changelog{}
+
+	err := parseReleases(&tokenStack, &changelog)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if !changelog.Releases[0].Yanked {
+		t.Errorf("expected release to be yanked, but wasn't")
+	}
+}
+
 func TestParse(t *testing.T) {
 	tokenStack := []token{
 		header1Title{},
@@ -363,7 +474,7 @@ func TestParse(t *testing.T) {
 }
 
 func TestParseInvalidHeaderReturnsError(t *testing.T) {
-	expectedError := errors.New("unexpected token")
+	expectedError := errors.New("0: expected changelog title, found text line")
 	tokenStack := []token{
 		textLine{Content: "Changelog"},
 		emptyLine{},
@@ -388,7 +499,7 @@ func TestParseInvalidHeaderReturnsError(t *testing.T) {
 }
 
 func TestParseIncorrectUnreleasedError(t *testing.T) {
-	expectedError := errors.New("unexpected token")
+	expectedError := errors.New("0: expected empty line, found change entry")
 	tokenStack := []token{
 		header1Title{Content: "Changelog"},
 		emptyLine{},
@@ -412,7 +523,7 @@ func TestParseIncorrectUnreleasedError(t *testing.T) {
 }
 
 func TestParseIncorrectReleaseError(t *testing.T) {
-	expectedError := errors.New("unexpected token")
+	expectedError := errors.New("0: expected empty line, found section title")
 	tokenStack := []token{
 		header1Title{Content: "Changelog"},
 		emptyLine{},
@@ -438,3 +549,79 @@ func TestParseIncorrectReleaseError(t *testing.T) {
 		t.Errorf("expected error to be '%v', but was '%v'", expectedError.Error(), err.Error())
 	}
 }
+
+func TestParseReportsPositionOfUnexpectedToken(t *testing.T) {
+	input := "# Changelog\n\nLorum ipsum.\n\n## [Unreleased]\n\n### Added\n\nNot an entry.\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	expectedError := &ParseError{Line: 9, Column: 1, Expected: "change entry", Found: "text line"}
+
+	_, err = Parse(tokens)
+
+	var parseError *ParseError
+	if !errors.As(err, &parseError) {
+		t.Fatalf("expected error to be a *ParseError, but was '%v'", err)
+	}
+	if *parseError != *expectedError {
+		t.Errorf("expected error to be '%v', but was '%v'", expectedError, parseError)
+	}
+}
+
+func TestParseErrorReportsColumnOfIndentedTokens(t *testing.T) {
+	err := &ParseError{Line: 12, Column: 3, Expected: "change entry", Found: "text line"}
+
+	if err.Error() != "12:3: expected change entry, found text line" {
+		t.Errorf("expected error to be '12:3: expected change entry, found text line', but was '%v'", err)
+	}
+}
+
+func TestParseReportsEndOfFile(t *testing.T) {
+	input := "# Changelog\n\nLorum ipsum.\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	expectedError := "4: expected release title, found end of file"
+
+	_, err = Parse(tokens)
+
+	if err == nil {
+		t.Fatalf("expected error to be '%v', but was nil", expectedError)
+	}
+	if err.Error() != expectedError {
+		t.Errorf("expected error to be '%v', but was '%v'", expectedError, err.Error())
+	}
+}
+
+func TestParseSetsLatestReleaseToNewestRelease(t *testing.T) {
+	input := "# Changelog\n\n## [Unreleased]\n\n## [1.1.0] - 2021-02-01\n\n### Added\n\n- Invoices.\n\n## [1.0.0] - 2021-01-01\n\n### Added\n\n- Parser.\n\n## [0.1.0] - 2020-12-01\n\n### Added\n\n- Lexer.\n"
+	tokens, err := LexReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	changelog, err := Parse(tokens)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if changelog.LatestRelease.Name != "1.1.0" {
+		t.Errorf("expected latest release to be '1.1.0', but was '%s'", changelog.LatestRelease.Name)
+	}
+}
+
+func TestNewEntryParsesDetails(t *testing.T) {
+	entry := NewEntry("⚠️ Dropped invoices by @octocat (#12)", "⚠️")
+
+	if !entry.Breaking {
+		t.Errorf("expected entry to be breaking, but it wasn't")
+	}
+	if len(entry.References) != 1 || entry.References[0].ID != "12" {
+		t.Errorf("expected references to be '[#12]', but was '%v'", entry.References)
+	}
+	if !reflect.DeepEqual(entry.Authors, []string{"@octocat"}) {
+		t.Errorf("expected authors to be '[@octocat]', but was '%v'", entry.Authors)
+	}
+}
@@ -0,0 +1,254 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ReferenceKind is the kind of thing an entry refers to.
+type ReferenceKind int
+
+// The kinds of references of entries. Issues and pull requests are those of
+// the forge that hosts the repository, while tracker references are keys of
+// issues in another issue tracker, such as "JIRA-456".
+const (
+	IssueReference ReferenceKind = iota
+	PullRequestReference
+	TrackerReference
+)
+
+// ParseReferenceKind parses the name of a kind of reference, one of "issue",
+// "pr" or "tracker".
+func ParseReferenceKind(name string) (ReferenceKind, error) {
+	switch strings.ToLower(name) {
+	case "issue":
+		return IssueReference, nil
+	case "pr", "pull-request":
+		return PullRequestReference, nil
+	case "tracker":
+		return TrackerReference, nil
+	}
+
+	return IssueReference, fmt.Errorf("unknown kind of reference '%s', must be one of issue, pr or tracker", name)
+}
+
+func (k ReferenceKind) String() string {
+	switch k {
+	case PullRequestReference:
+		return "pull-request"
+	case TrackerReference:
+		return "tracker"
+	}
+
+	return "issue"
+}
+
+// MarshalText writes the kind by its name, so that it is exported as such.
+func (k ReferenceKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText reads a kind from its name.
+func (k *ReferenceKind) UnmarshalText(text []byte) error {
+	kind, err := ParseReferenceKind(string(text))
+	if err != nil {
+		return err
+	}
+	*k = kind
+
+	return nil
+}
+
+// Reference is an issue, pull request or key of an issue tracker that an entry
+// refers to, such as the "#123" of "Fixed a bug (#123)". The URL is only known
+// when the reference is a link.
+type Reference struct {
+	Kind ReferenceKind `json:"kind" yaml:"kind"`
+	ID   string        `json:"id" yaml:"id"`
+	URL  string        `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// Label returns how the reference is written, such as "#123" or "JIRA-456".
+func (r Reference) Label() string {
+	if r.Kind == TrackerReference {
+		return r.ID
+	}
+
+	return "#" + r.ID
+}
+
+// String returns the reference in Markdown, as a link if its URL is known. A
+// pull request without URL is written as "!456", so that it is parsed as a
+// pull request rather than an issue.
+func (r Reference) String() string {
+	switch {
+	case r.URL != "":
+		return fmt.Sprintf("[%s](%s)", r.Label(), r.URL)
+	case r.Kind == PullRequestReference:
+		return "!" + r.ID
+	}
+
+	return r.Label()
+}
+
+// ReferenceLinks holds the templates of the links to references, using the
+// placeholders {repo} for the URL of the repository and {id} for the ID of
+// the reference, such as "{repo}/issues/{id}". References of a kind without
+// template are not linked.
+type ReferenceLinks struct {
+	Issue       string
+	PullRequest string
+	Tracker     string
+}
+
+// referenceLinks maps the link providers of the supported forges to the links
+// to their issues and pull requests.
+var referenceLinks = map[LinkProvider]ReferenceLinks{
+	GitHub:    {Issue: "{repo}/issues/{id}", PullRequest: "{repo}/pull/{id}"},
+	GitLab:    {Issue: "{repo}/-/issues/{id}", PullRequest: "{repo}/-/merge_requests/{id}"},
+	Bitbucket: {Issue: "{repo}/issues/{id}", PullRequest: "{repo}/pull-requests/{id}"},
+	Gitea:     {Issue: "{repo}/issues/{id}", PullRequest: "{repo}/pulls/{id}"},
+}
+
+// ReferenceLinks returns the links to the issues and pull requests of the forge
+// that hosts the repository of the changelog, which are empty for custom hosts.
+func (c Changelog) ReferenceLinks() ReferenceLinks {
+	return referenceLinks[c.linkProvider()]
+}
+
+// Reference returns a reference of the given kind with the given ID, linked to
+// the URL created from the template of its kind.
+func (l ReferenceLinks) Reference(repository string, kind ReferenceKind, id string) Reference {
+	template := l.Issue
+	switch kind {
+	case PullRequestReference:
+		template = l.PullRequest
+	case TrackerReference:
+		template = l.Tracker
+	}
+
+	reference := Reference{Kind: kind, ID: strings.TrimPrefix(id, "#")}
+	if template != "" {
+		reference.URL = strings.NewReplacer("{repo}", repository, "{id}", reference.ID).Replace(template)
+	}
+
+	return reference
+}
+
+var referenceGroupRegex = regexp.MustCompile(`\(((?:[^()]|\([^()]*\))+)\)\.?\s*$`)
+var referenceRegex = regexp.MustCompile(`^(?:([#!])(\d+)|([A-Z][A-Z0-9]*-\d+))$`)
+var referenceLinkRegex = regexp.MustCompile(`^\[([^\]]+)\]\((\S+)\)$`)
+var pullRequestURLRegex = regexp.MustCompile(`/(?:pull|pulls|merge_requests|pull-requests)/\d+`)
+
+// parseReferences returns the references in the group of references that ends
+// the description, optionally followed by a period, such as "(#123, [JIRA-456](https://jira.example.com/browse/JIRA-456))".
+// The group may be followed by the attribution of the entry. It returns nil if
+// the description does not end with a group of references.
+func parseReferences(description string) []Reference {
+	return parseReferenceGroup(trimAuthors(description))
+}
+
+// parseReferenceGroup returns the references in the group of references that
+// ends the given text, or nil if it does not end with one.
+func parseReferenceGroup(text string) []Reference {
+	match := referenceGroupRegex.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+
+	references := []Reference{}
+	for _, item := range strings.Split(match[1], ",") {
+		reference, ok := parseReference(strings.TrimSpace(item))
+		if !ok {
+			return nil
+		}
+		references = append(references, reference)
+	}
+
+	return references
+}
+
+// parseReference parses a single reference, such as "#123", "!456",
+// "JIRA-456" or a link of which the text is one of those.
+func parseReference(text string) (Reference, bool) {
+	url := ""
+	if match := referenceLinkRegex.FindStringSubmatch(text); match != nil {
+		text, url = match[1], match[2]
+	}
+
+	match := referenceRegex.FindStringSubmatch(text)
+	if match == nil {
+		return Reference{}, false
+	}
+
+	switch {
+	case match[3] != "":
+		return Reference{Kind: TrackerReference, ID: match[3], URL: url}, true
+	case match[1] == "!" || pullRequestURLRegex.MatchString(url):
+		return Reference{Kind: PullRequestReference, ID: match[2], URL: url}, true
+	}
+
+	return Reference{Kind: IssueReference, ID: match[2], URL: url}, true
+}
+
+// AddReference adds the reference to the entry, and to the group of references
+// that ends its description. A reference the entry already has is not added
+// again.
+func (e *Entry) AddReference(reference Reference) {
+	for _, existing := range append(parseReferences(e.Description), e.References...) {
+		if existing.Kind == reference.Kind && existing.ID == reference.ID {
+			return
+		}
+	}
+
+	if parseReferences(e.Description) != nil {
+		index := strings.LastIndex(trimAuthors(e.Description), ")")
+		e.Description = e.Description[:index] + ", " + reference.String() + e.Description[index:]
+	} else {
+		e.Description += " (" + reference.String() + ")"
+	}
+	e.References = append(e.References, reference)
+}
+
+// References returns the references of the entries of the release, including
+// nested entries, in the order they appear. Only references of the given kinds
+// are returned, or all references when no kinds are given. References to the
+// same thing are returned once.
+func (r Release) References(kinds ...ReferenceKind) []Reference {
+	result := []Reference{}
+	seen := map[string]bool{}
+
+	var collect func(entries []Entry)
+	collect = func(entries []Entry) {
+		for _, entry := range entries {
+			for _, reference := range entry.References {
+				key := reference.Kind.String() + reference.ID
+				if seen[key] || !hasKind(kinds, reference.Kind) {
+					continue
+				}
+				seen[key] = true
+				result = append(result, reference)
+			}
+			collect(entry.Children)
+		}
+	}
+	for _, section := range r.Sections {
+		collect(section.Entries)
+	}
+
+	return result
+}
+
+func hasKind(kinds []ReferenceKind, kind ReferenceKind) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, candidate := range kinds {
+		if candidate == kind {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,143 @@
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseReferences(t *testing.T) {
+	testCases := []struct {
+		description        string
+		expectedReferences []Reference
+	}{
+		{"Fixed a bug (#123)", []Reference{{Kind: IssueReference, ID: "123"}}},
+		{"Fixed a bug. (#123, !456)", []Reference{{Kind: IssueReference, ID: "123"}, {Kind: PullRequestReference, ID: "456"}}},
+		{"Fixed a bug ([JIRA-456](https://jira.example.com/browse/JIRA-456))", []Reference{{Kind: TrackerReference, ID: "JIRA-456", URL: "https://jira.example.com/browse/JIRA-456"}}},
+		{"Fixed a bug ([#7](https://github.com/mrombout/gochange/pull/7), [#8](https://github.com/mrombout/gochange/issues/8))", []Reference{
+			{Kind: PullRequestReference, ID: "7", URL: "https://github.com/mrombout/gochange/pull/7"},
+			{Kind: IssueReference, ID: "8", URL: "https://github.com/mrombout/gochange/issues/8"},
+		}},
+		{"Fixed a bug\nthat wraps (#9)", []Reference{{Kind: IssueReference, ID: "9"}}},
+		{"Fixed a bug (#123).", []Reference{{Kind: IssueReference, ID: "123"}}},
+		{"Fixed a bug (see the docs)", nil},
+		{"Fixed a bug (#123) in the parser", nil},
+		{"Fixed a bug (#123, and more)", nil},
+		{"Fixed the (#123) bug.", nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			result := parseReferences(testCase.description)
+
+			if !reflect.DeepEqual(result, testCase.expectedReferences) {
+				t.Errorf("expected references to be %v, but was %v", testCase.expectedReferences, result)
+			}
+		})
+	}
+}
+
+func TestParseEntryReferences(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/references.md")
+
+	references := changelog.Releases[0].References()
+	expectedReferences := []Reference{
+		{Kind: IssueReference, ID: "12", URL: "https://github.com/mrombout/gochange/issues/12"},
+		{Kind: PullRequestReference, ID: "13", URL: "https://github.com/mrombout/gochange/pull/13"},
+		{Kind: TrackerReference, ID: "JIRA-456", URL: "https://jira.example.com/browse/JIRA-456"},
+		{Kind: IssueReference, ID: "14"},
+	}
+	if !reflect.DeepEqual(references, expectedReferences) {
+		t.Errorf("expected references to be %v, but was %v", expectedReferences, references)
+	}
+
+	issues := changelog.Releases[0].References(IssueReference, TrackerReference)
+	if len(issues) != 3 || issues[1].ID != "JIRA-456" {
+		t.Errorf("expected only issues and tracker keys, but was %v", issues)
+	}
+}
+
+func TestAddReference(t *testing.T) {
+	repository := "https://github.com/mrombout/gochange"
+	referenceLinks := Changelog{Links: GitHub}.ReferenceLinks()
+
+	testCases := []struct {
+		name                string
+		description         string
+		reference           Reference
+		expectedDescription string
+	}{
+		{"first", "A bug.", referenceLinks.Reference(repository, IssueReference, "12"), "A bug. ([#12](https://github.com/mrombout/gochange/issues/12))"},
+		{"another", "A bug. (#12)", referenceLinks.Reference(repository, PullRequestReference, "#13"), "A bug. (#12, [#13](https://github.com/mrombout/gochange/pull/13))"},
+		{"unlinked", "A bug.", ReferenceLinks{}.Reference(repository, TrackerReference, "JIRA-456"), "A bug. (JIRA-456)"},
+		{"period", "A bug (#12).", Reference{Kind: IssueReference, ID: "13"}, "A bug (#12, #13)."},
+		{"parenthesis", "A bug (in the parser).", Reference{Kind: IssueReference, ID: "12"}, "A bug (in the parser). (#12)"},
+		{"unlinked pull request", "A bug.", Reference{Kind: PullRequestReference, ID: "13"}, "A bug. (!13)"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			entry := Entry{Description: testCase.description, References: parseReferences(testCase.description)}
+
+			entry.AddReference(testCase.reference)
+			entry.AddReference(testCase.reference)
+
+			if entry.Description != testCase.expectedDescription {
+				t.Errorf("expected description to be '%s', but was '%s'", testCase.expectedDescription, entry.Description)
+			}
+			if !reflect.DeepEqual(parseReferences(entry.Description), entry.References) {
+				t.Errorf("expected references %v to be parsed from the description, but was %v", entry.References, parseReferences(entry.Description))
+			}
+		})
+	}
+}
+
+func TestAddReference_WhenDescriptionHasReference_DoesNotAddItAgain(t *testing.T) {
+	entry := Entry{Description: "Dropped foo (#12)"}
+
+	entry.AddReference(Reference{Kind: IssueReference, ID: "12", URL: "https://github.com/mrombout/gochange/issues/12"})
+
+	if entry.Description != "Dropped foo (#12)" {
+		t.Errorf("expected description to be 'Dropped foo (#12)', but was '%s'", entry.Description)
+	}
+}
+
+func TestReferenceLinks(t *testing.T) {
+	testCases := []struct {
+		provider      LinkProvider
+		expectedIssue string
+		expectedPull  string
+	}{
+		{GitHub, "https://example.com/repo/issues/1", "https://example.com/repo/pull/1"},
+		{GitLab, "https://example.com/repo/-/issues/1", "https://example.com/repo/-/merge_requests/1"},
+		{Bitbucket, "https://example.com/repo/issues/1", "https://example.com/repo/pull-requests/1"},
+		{Gitea, "https://example.com/repo/issues/1", "https://example.com/repo/pulls/1"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expectedPull, func(t *testing.T) {
+			links := Changelog{Links: testCase.provider}.ReferenceLinks()
+
+			issue := links.Reference("https://example.com/repo", IssueReference, "1")
+			pull := links.Reference("https://example.com/repo", PullRequestReference, "1")
+
+			if issue.URL != testCase.expectedIssue || pull.URL != testCase.expectedPull {
+				t.Errorf("expected links to be '%s' and '%s', but were '%s' and '%s'", testCase.expectedIssue, testCase.expectedPull, issue.URL, pull.URL)
+			}
+		})
+	}
+}
+
+func TestImportParsesReferences(t *testing.T) {
+	input := `{"schema": 1, "releases": [{"name": "1.0.0", "sections": [{"name": "Fixed", "entries": [{"description": "A bug (#12)"}]}]}]}`
+
+	changelog, err := Import(strings.NewReader(input), ExportJSON)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	references := changelog.Releases[0].References()
+	if len(references) != 1 || references[0].ID != "12" {
+		t.Errorf("expected reference #12 to be parsed, but was %v", references)
+	}
+}
@@ -0,0 +1,151 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ReleaseFormat is the format a single release is rendered in.
+type ReleaseFormat int
+
+// The formats a single release can be rendered in.
+const (
+	MarkdownFormat ReleaseFormat = iota
+	TextFormat
+	JSONFormat
+	HTMLFormat
+)
+
+// ParseReleaseFormat parses the name of a release format, one of "markdown",
+// "text", "json" or "html".
+func ParseReleaseFormat(name string) (ReleaseFormat, error) {
+	switch strings.ToLower(name) {
+	case "markdown", "md":
+		return MarkdownFormat, nil
+	case "text", "txt":
+		return TextFormat, nil
+	case "json":
+		return JSONFormat, nil
+	case "html":
+		return HTMLFormat, nil
+	}
+
+	return MarkdownFormat, fmt.Errorf("unknown format '%s', must be one of markdown, text, json or html", name)
+}
+
+// ReleaseOptions configures how RenderRelease renders a release.
+type ReleaseOptions struct {
+	Format ReleaseFormat
+
+	// OmitTitle leaves out the title of the release, so that only its sections
+	// are rendered, as for the body of a release on a forge. The JSON format
+	// always includes the name and date of the release.
+	OmitTitle bool
+
+	// GroupBreaking moves the breaking changes of the release from their
+	// sections to a section of their own, named "Breaking changes", before all
+	// other sections.
+	GroupBreaking bool
+}
+
+// RenderRelease renders a single release to the given writer, such as the notes
+// of a release to publish on a forge. The unreleased changes are recognised by
+// their name "Unreleased".
+func RenderRelease(release Release, writer io.Writer, options ReleaseOptions) error {
+	if options.GroupBreaking {
+		release = groupBreaking(release)
+	}
+
+	switch options.Format {
+	case TextFormat:
+		_, err := io.WriteString(writer, renderReleaseText(release, options.OmitTitle))
+		return err
+	case JSONFormat:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		release.Sections = sections(release)
+		return encoder.Encode(release)
+	case HTMLFormat:
+		name := "release"
+		if options.OmitTitle {
+			name = "sections"
+		}
+		return htmlTemplate.ExecuteTemplate(writer, name, release)
+	}
+
+	name := "sections"
+	switch {
+	case options.OmitTitle:
+	case isUnreleased(release):
+		name = "unreleased"
+	default:
+		name = "release"
+	}
+	fragment, err := renderFragment(name, release)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(writer, strings.TrimRight(fragment, "\n")+"\n")
+	return err
+}
+
+// isUnreleased returns whether the release holds the unreleased changes.
+func isUnreleased(release Release) bool {
+	return strings.EqualFold(release.Name, "Unreleased") && release.Date == ""
+}
+
+// renderReleaseText renders a release as plain text, with the markup of the
+// descriptions of the entries removed.
+func renderReleaseText(release Release, omitTitle bool) string {
+	out := strings.Builder{}
+	if !omitTitle {
+		out.WriteString(release.Name)
+		if release.Date != "" {
+			out.WriteString(" - " + release.Date)
+		}
+		if release.Yanked {
+			out.WriteString(" (yanked)")
+		}
+		out.WriteString("\n\n")
+	}
+
+	for i, section := range sections(release) {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		out.WriteString(section.Name + ":\n")
+		for _, entry := range section.Entries {
+			for _, line := range entryLines(plainEntry(entry)) {
+				out.WriteString(line + "\n")
+			}
+		}
+	}
+
+	return out.String()
+}
+
+var markdownLinkRegex = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+var markdownEmphasisRegex = regexp.MustCompile(`(\*\*|__|\*)([^*_]+)(\*\*|__|\*)`)
+
+// plainText removes the markup of Markdown text, links are written as their
+// text followed by their URL.
+func plainText(text string) string {
+	text = markdownLinkRegex.ReplaceAllString(text, "$1 ($2)")
+	text = markdownEmphasisRegex.ReplaceAllString(text, "$2")
+
+	return strings.ReplaceAll(text, "`", "")
+}
+
+func plainEntry(entry Entry) Entry {
+	result := Entry{Description: plainText(entry.Description)}
+	for _, child := range entry.Children {
+		result.Children = append(result.Children, plainEntry(child))
+	}
+
+	return result
+}
@@ -0,0 +1,160 @@
+package changelog
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+var renderedRelease = Release{
+	Name: "1.1.0",
+	Date: "2021-03-01",
+	Sections: []Section{
+		{Name: Added, Entries: []Entry{{Description: "A [parser](https://example.com/parser) for `<config>` files.", Children: []Entry{{Description: "With **strict** mode."}}}}},
+		{Name: Changed},
+		{Name: Fixed, Entries: []Entry{{Description: "A bug & another."}}},
+	},
+}
+
+func TestRenderRelease(t *testing.T) {
+	testCases := []struct {
+		name           string
+		release        Release
+		options        ReleaseOptions
+		expectedOutput string
+	}{
+		{"markdown", renderedRelease, ReleaseOptions{Format: MarkdownFormat}, "## [1.1.0] - 2021-03-01\n\n" +
+			"### Added\n\n- A [parser](https://example.com/parser) for `<config>` files.\n  - With **strict** mode.\n\n" +
+			"### Fixed\n\n- A bug & another.\n"},
+		{"markdown without title", renderedRelease, ReleaseOptions{Format: MarkdownFormat, OmitTitle: true}, "### Added\n\n" +
+			"- A [parser](https://example.com/parser) for `<config>` files.\n  - With **strict** mode.\n\n" +
+			"### Fixed\n\n- A bug & another.\n"},
+		{"markdown unreleased", Release{Name: "Unreleased", Sections: []Section{{Name: Fixed, Entries: []Entry{{Description: "A bug."}}}}}, ReleaseOptions{}, "## [Unreleased]\n\n### Fixed\n\n- A bug.\n"},
+		{"text", renderedRelease, ReleaseOptions{Format: TextFormat}, "1.1.0 - 2021-03-01\n\n" +
+			"Added:\n- A parser (https://example.com/parser) for <config> files.\n  - With strict mode.\n\n" +
+			"Fixed:\n- A bug & another.\n"},
+		{"json", renderedRelease, ReleaseOptions{Format: JSONFormat, OmitTitle: true}, `{
+  "name": "1.1.0",
+  "date": "2021-03-01",
+  "sections": [
+    {
+      "name": "Added",
+      "entries": [
+        {
+          "description": "A [parser](https://example.com/parser) for ` + "`<config>`" + ` files.",
+          "children": [
+            {
+              "description": "With **strict** mode."
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "name": "Fixed",
+      "entries": [
+        {
+          "description": "A bug & another."
+        }
+      ]
+    }
+  ]
+}
+`},
+		{"html", renderedRelease, ReleaseOptions{Format: HTMLFormat}, "<h2>1.1.0 <time datetime=\"2021-03-01\">2021-03-01</time></h2>\n" +
+			"<h3>Added</h3>\n<ul>\n" +
+			"<li>A <a href=\"https://example.com/parser\">parser</a> for <code>&lt;config&gt;</code> files.\n<ul>\n<li>With <strong>strict</strong> mode.</li>\n</ul></li>\n" +
+			"</ul>\n<h3>Fixed</h3>\n<ul>\n<li>A bug &amp; another.</li>\n</ul>\n"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			output := strings.Builder{}
+
+			err := RenderRelease(testCase.release, &output, testCase.options)
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if output.String() != testCase.expectedOutput {
+				t.Errorf("expected output to be\n%s\nbut was\n%s", testCase.expectedOutput, output.String())
+			}
+		})
+	}
+}
+
+func TestInlineHTML(t *testing.T) {
+	testCases := []struct {
+		markdown       string
+		expectedOutput string
+	}{
+		{"Plain <b>text</b>.", "Plain &lt;b&gt;text&lt;/b&gt;."},
+		{"A `**code**` span.", "A <code>**code**</code> span."},
+		{"An *emphasised* link to [the **docs**](https://example.com/?a=1&b=2).", `An <em>emphasised</em> link to <a href="https://example.com/?a=1&amp;b=2">the <strong>docs</strong></a>.`},
+		{"An [unsafe](javascript:void) link.", "An unsafe link."},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.markdown, func(t *testing.T) {
+			result := string(inlineHTML(testCase.markdown))
+
+			if result != testCase.expectedOutput {
+				t.Errorf("expected output to be '%s', but was '%s'", testCase.expectedOutput, result)
+			}
+		})
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/export.md")
+	expectedOutput, err := ioutil.ReadFile("testdata/page.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := strings.Builder{}
+
+	err = RenderHTML(changelog, &output)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if output.String() != string(expectedOutput) {
+		t.Errorf("expected output to be\n%s\nbut was\n%s", expectedOutput, output.String())
+	}
+}
+
+func TestRenderHTMLWithoutURLLinksNoComparisons(t *testing.T) {
+	changelog := parseLosslessTestdata(t, "testdata/export.md")
+	changelog.URL = ""
+	output := strings.Builder{}
+
+	err := RenderHTML(changelog, &output)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if strings.Contains(output.String(), "/compare/") {
+		t.Errorf("expected no compare links, but was\n%s", output.String())
+	}
+}
+
+func TestAnchor(t *testing.T) {
+	testCases := []struct {
+		name           string
+		expectedAnchor string
+	}{
+		{"Unreleased", "unreleased"},
+		{"1.2.0-rc.1+build.5", "1.2.0-rc.1-build.5"},
+		{"Version 2 (beta)", "version-2-beta"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := anchor(testCase.name)
+
+			if result != testCase.expectedAnchor {
+				t.Errorf("expected anchor to be '%s', but was '%s'", testCase.expectedAnchor, result)
+			}
+		})
+	}
+}
@@ -1,119 +1,168 @@
 package changelog
 
 import (
-	"html/template"
 	"io"
+	"strings"
+	"text/template"
 )
 
-// Render renders a changelog in Markdown to the given writer.
-func Render(changelog Changelog, writer io.Writer) {
-	template, err := template.New("test").Funcs(template.FuncMap{
-		"deref": func(i *Release) Release { return *i },
-	}).Parse(`# Changelog
+// sections returns the sections of the given release that have entries, in the
+// order they are rendered in.
+func sections(release Release) []Section {
+	result := []Section{}
+	for _, section := range release.Sections {
+		if len(section.Entries) > 0 {
+			result = append(result, section)
+		}
+	}
+
+	return result
+}
+
+// entryLines returns the lines of the given entry, continuation lines and
+// nested entries are indented below the entry they belong to.
+func entryLines(entry Entry) []string {
+	return indentedEntryLines(entry, "")
+}
+
+func indentedEntryLines(entry Entry, indent string) []string {
+	descriptionLines := strings.Split(entry.Description, "\n")
+
+	lines := []string{indent + "- " + descriptionLines[0]}
+	for _, line := range descriptionLines[1:] {
+		lines = append(lines, indent+"  "+escapeContinuation(line))
+	}
+	for _, child := range entry.Children {
+		lines = append(lines, indentedEntryLines(child, indent+"  ")...)
+	}
 
+	return lines
+}
+
+// escapeContinuation escapes a continuation line of an entry that would
+// otherwise be read as a nested entry, by escaping its bullet as Markdown does.
+// The descriptions of entries are Markdown themselves, so any other text is
+// written as it is.
+func escapeContinuation(line string) string {
+	if isChangeEntry(line) {
+		indent := indentation(line)
+		return line[:indent] + "\\" + line[indent:]
+	}
+
+	return line
+}
+
+// markdownTemplate renders a changelog in Markdown. Every part of the changelog
+// is a separately named template, so that parts can also be rendered on their
+// own when rendering losslessly. Compare links are only rendered for a
+// changelog with the URL of its repository. Nothing is escaped by the
+// template, since the title, description and entries of a changelog are
+// Markdown already.
+var markdownTemplate = template.Must(template.New("markdown").Funcs(template.FuncMap{
+	"sections":   sections,
+	"entryLines": entryLines,
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"join":       join,
+	"replace":    replace,
+	"trim":       strings.TrimSpace,
+}).Parse(`
+{{- define "changelog" -}}
+{{template "header" .}}{{template "unreleased" .Unreleased}}{{range .Releases}}{{template "release" .}}{{end}}{{template "links" .}}
+{{- end -}}
+
+{{- define "header" -}}
+{{template "title" .}}
+{{template "description" .}}
+{{- end -}}
+
+{{- define "title" -}}
+# {{if .Title}}{{.Title}}{{else}}Changelog{{end}}
+{{end -}}
+
+{{- define "description" -}}
 {{.Description}}
 
+{{end -}}
+
+{{- define "unreleased" -}}
 ## [Unreleased]
-{{- if .Unreleased.Added }}
-
-### Added
-{{range .Unreleased.Added }}
-- {{ .Description -}}
-{{- end }}
-{{- end }}
-{{- if .Unreleased.Changed }}
-
-### Changed
-{{range .Unreleased.Changed }}
-- {{ .Description -}}
-{{- end }}
-{{- end }}
-{{- if .Unreleased.Deprecated }}
-
-### Deprecated
-{{range .Unreleased.Deprecated }}
-- {{ .Description -}}
-{{- end }}
-{{- end }}
-{{- if .Unreleased.Removed }}
-
-### Removed
-{{range .Unreleased.Removed }}
-- {{ .Description -}}
-{{- end }}
-{{- end }}
-{{- if .Unreleased.Fixed }}
-
-### Fixed
-{{range .Unreleased.Fixed }}
-- {{ .Description -}}
-{{- end }}
-{{-  end }}
-{{- if .Unreleased.Security }}
-
-### Security
-{{range .Unreleased.Security }}
-- {{ .Description -}}
-{{- end }}
-{{- end }}
-{{-  range .Releases }}
-
-## [{{.Name}}] - {{.Date}}
-{{- if .Added }}
-
-### Added
-{{range .Added }}
-- {{ .Description -}}
-{{- end }}
-{{- end }}
-{{- if .Changed }}
-
-### Changed
-{{range .Changed }}
-- {{ .Description -}}
-{{- end }}
-{{- end }}
-{{- if .Deprecated }}
-
-### Deprecated
-{{range .Deprecated }}
-- {{ .Description -}}
-{{- end }}
-{{- end }}
-{{- if .Removed }}
-
-### Removed
-{{range .Removed }}
-- {{ .Description -}}
-{{- end }}
-{{- end }}
-{{- if .Fixed }}
-
-### Fixed
-{{range .Fixed }}
-- {{ .Description -}}
-{{- end }}
-{{- end }}
-{{- if .Security }}
-
-### Security
-{{range .Security }}
-- {{ .Description -}}
-{{- end }}{{- end }}{{ end }}
-
-[Unreleased]: {{.URL}}{{.LatestRelease.Name}}...HEAD
-{{- range $i, $e := .Releases}}
-{{- if $e.PreviousRelease}}
-[{{$e.Name}}]: {{$.URL}}{{(deref $e.PreviousRelease).Name}}...{{$e.Name}}
-{{- end}}
+
+{{template "sections" .}}
+{{- end -}}
+
+{{- define "release" -}}
+{{template "release title" .}}
+{{template "sections" .}}
+{{- end -}}
+
+{{- define "release title" -}}
+## [{{.Name}}] - {{.Date}}{{if .Yanked}} [YANKED]{{end}}
+{{end -}}
+
+{{- define "sections" -}}
+{{range sections .}}{{template "section" .}}{{end}}
+{{- end -}}
+
+{{- define "section" -}}
+### {{.Name}}
+
+{{range .Entries}}{{template "entry" .}}{{end}}
+{{end -}}
+
+{{- define "entry" -}}
+{{range entryLines .}}{{.}}
+{{end}}
+{{- end -}}
+
+{{- define "links" -}}
+{{if .URL -}}
+[Unreleased]: {{.CompareURL .LatestRelease.Name "HEAD"}}
+{{range .Releases}}{{if .PreviousRelease}}[{{.Name}}]: {{$.CompareURL .PreviousRelease.Name .Name}}
+{{end}}{{end}}
 {{- end}}
-`)
-	if err != nil {
-		panic(err)
+{{- end -}}
+`))
+
+// join joins the texts with the separator, with the separator first so that it
+// reads well in a pipeline, such as {{.Names | join ", "}}.
+func join(separator string, texts []string) string {
+	return strings.Join(texts, separator)
+}
+
+// replace replaces old by new in the text, with the text last so that it reads
+// well in a pipeline, such as {{.Name | replace "-" " "}}.
+func replace(old string, new string, text string) string {
+	return strings.ReplaceAll(text, old, new)
+}
+
+// Render renders a changelog in Markdown to the given writer.
+//
+// A changelog that was parsed with the Lossless option is rendered losslessly,
+// only the parts that were changed since it was parsed are rendered anew.
+func Render(changelog Changelog, writer io.Writer, options ...RenderOption) error {
+	renderOptions := renderOptions{}
+	for _, option := range options {
+		option(&renderOptions)
+	}
+	if renderOptions.template != nil {
+		return renderOptions.template.template.ExecuteTemplate(writer, "changelog", changelog)
 	}
 
-	err = template.Execute(writer, changelog)
-	if err != nil {
-		panic(err)
+	if changelog.document != nil {
+		return renderLossless(changelog, changelog.document, writer)
 	}
+
+	return markdownTemplate.ExecuteTemplate(writer, "changelog", changelog)
+}
+
+// renderFragment renders a single named part of the markdown template to a
+// string.
+func renderFragment(name string, data interface{}) (string, error) {
+	builder := strings.Builder{}
+	if err := markdownTemplate.ExecuteTemplate(&builder, name, data); err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
 }
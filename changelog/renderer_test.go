@@ -1,6 +1,7 @@
 package changelog
 
 import (
+	"errors"
 	"io/ioutil"
 	"strings"
 	"testing"
@@ -11,9 +12,14 @@ func TestRender(t *testing.T) {
 	release020 := Release{
 		Name: "0.2.0",
 		Date: "2018-08-14",
-		Added: []Entry{
-			Entry{
-				Description: "Some stuff.",
+		Sections: []Section{
+			{
+				Name: Added,
+				Entries: []Entry{
+					Entry{
+						Description: "Some stuff.",
+					},
+				},
 			},
 		},
 		PreviousRelease: &Release{
@@ -23,38 +29,54 @@ func TestRender(t *testing.T) {
 	release100 := Release{
 		Name: "1.0.0",
 		Date: "2018-12-28",
-		Added: []Entry{
-			Entry{
-				Description: "Some stuff.",
+		Sections: []Section{
+			{
+				Name: Added,
+				Entries: []Entry{
+					Entry{
+						Description: "Some stuff.",
+					},
+				},
 			},
 		},
 		PreviousRelease: &release020,
 	}
 
 	currentChangelog := Changelog{
-		URL:         "http://github.com/mrombout/gochange/",
+		URL:         "http://github.com/mrombout/gochange",
 		Description: "Lorum ipsum dolor sit amet consectatur.",
 		Unreleased: Release{
 			Name: "Unreleased",
-			Added: []Entry{
-				Entry{
-					Description: "Some more stuff.",
-				},
-				Entry{
-					Description: "Even more stuff.",
+			Sections: []Section{
+				{
+					Name: Added,
+					Entries: []Entry{
+						Entry{
+							Description: "Some more stuff.",
+						},
+						Entry{
+							Description: "Even more stuff.",
+						},
+					},
 				},
-			},
-			Removed: []Entry{
-				Entry{
-					Description: "Easter egg.",
-				},
-				Entry{
-					Description: "Bitcoin Miner",
+				{
+					Name: Changed,
+					Entries: []Entry{
+						Entry{
+							Description: "Some things.",
+						},
+					},
 				},
-			},
-			Changed: []Entry{
-				Entry{
-					Description: "Some things.",
+				{
+					Name: Removed,
+					Entries: []Entry{
+						Entry{
+							Description: "Easter egg.",
+						},
+						Entry{
+							Description: "Bitcoin Miner",
+						},
+					},
 				},
 			},
 		},
@@ -71,12 +93,230 @@ func TestRender(t *testing.T) {
 	actualOutput := strings.Builder{}
 
 	// act
-	Render(currentChangelog, &actualOutput)
+	err = Render(currentChangelog, &actualOutput)
 
 	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
 	actualOutputStr := actualOutput.String()
 	actualExpectedOutputStr := string(expectedOutput)
 	if actualOutputStr != actualExpectedOutputStr {
 		t.Errorf("actual output does not match expected output")
 	}
 }
+
+func TestRenderRoundTripsNestedEntries(t *testing.T) {
+	// arrange
+	expectedOutput, err := ioutil.ReadFile("testdata/nested.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := LexReader(strings.NewReader(string(expectedOutput)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentChangelog, err := Parse(tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actualOutput := strings.Builder{}
+
+	// act
+	err = Render(currentChangelog, &actualOutput)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if actualOutput.String() != string(expectedOutput) {
+		t.Errorf("expected output to be\n%s\nbut was\n%s", expectedOutput, actualOutput.String())
+	}
+}
+
+func TestRenderYankedRelease(t *testing.T) {
+	// arrange
+	release := Release{
+		Name:   "0.0.5",
+		Date:   "2014-12-13",
+		Yanked: true,
+	}
+
+	// act
+	result, err := renderFragment("release title", release)
+
+	// assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "## [0.0.5] - 2014-12-13 [YANKED]\n" {
+		t.Errorf("expected result to be %s, but got %s", "## [0.0.5] - 2014-12-13 [YANKED]", result)
+	}
+}
+
+func TestRenderSectionsInReleaseOrder(t *testing.T) {
+	// arrange
+	release := Release{
+		Name: "1.0.0",
+		Date: "2018-12-28",
+		Sections: []Section{
+			{Name: "Performance", Entries: []Entry{{Description: "Faster parsing."}}},
+			{Name: Added, Entries: []Entry{{Description: "A feature."}}},
+			{Name: Fixed},
+		},
+	}
+	expectedOutput := "## [1.0.0] - 2018-12-28\n\n### Performance\n\n- Faster parsing.\n\n### Added\n\n- A feature.\n\n"
+
+	// act
+	result, err := renderFragment("release", release)
+
+	// assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != expectedOutput {
+		t.Errorf("expected result to be\n%s\nbut got\n%s", expectedOutput, result)
+	}
+}
+
+type erroringWriter struct{}
+
+func (erroringWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("this error is expected")
+}
+
+func TestRenderReturnsWriteError(t *testing.T) {
+	// act
+	err := Render(newChangelog(), erroringWriter{})
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+// roundTripEntries are descriptions of entries that must be rendered exactly as
+// they were written.
+var roundTripEntries = []string{
+	`Fixed "quoted" <T> generics & maps.`,
+	"Added `map[string]interface{}` and `<-chan T` support.",
+	"Changed the [docs](https://example.com/docs?a=1&b=2#anchor) and <https://example.com>.",
+	"Removed **bold**, _italic_ and ~~struck~~ text.",
+	"Fixed `{{ .Template }}` actions and %s verbs.",
+	`Escaped \* asterisks, \_ underscores and C:\path\to\file.`,
+	"Added <br> and &amp; entities.",
+	"Changed 'single' quotes, emoji 🎉 and non-ASCII ümlauts.",
+	"Fixed a bug.\nThat continues on a second line with <html> & \"quotes\".",
+}
+
+func TestRenderRoundTripsEntryText(t *testing.T) {
+	for _, description := range roundTripEntries {
+		t.Run(description, func(t *testing.T) {
+			// arrange
+			lines := strings.Split(description, "\n")
+			input := "# Changelog\n\nDescription with <html> & \"quotes\".\n\n## [Unreleased]\n\n## [1.0.0] - 2021-03-01\n\n### Fixed\n\n" +
+				"- " + strings.Join(lines, "\n  ") + "\n\n" +
+				"[Unreleased]: https://github.com/mrombout/gochange/compare/1.0.0...HEAD\n"
+			tokens, err := LexReader(strings.NewReader(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			currentChangelog, err := Parse(tokens)
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			actualOutput := strings.Builder{}
+
+			// act
+			err = Render(currentChangelog, &actualOutput)
+
+			// assert
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if actualOutput.String() != input {
+				t.Errorf("expected output to be\n%s\nbut was\n%s", input, actualOutput.String())
+			}
+		})
+	}
+}
+
+func TestRenderedEntryParsesAsSameEntry(t *testing.T) {
+	for _, description := range append(roundTripEntries, "A list:\n- that is not nested.") {
+		t.Run(description, func(t *testing.T) {
+			// arrange
+			entry := Entry{Description: description, Children: []Entry{{Description: description}}}
+			currentChangelog := newChangelog()
+			currentChangelog.Unreleased.AddEntry(Fixed, entry)
+			rendered := strings.Builder{}
+			if err := Render(currentChangelog, &rendered); err != nil {
+				t.Fatal(err)
+			}
+
+			// act
+			tokens, err := LexReader(strings.NewReader(rendered.String()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := Parse(tokens)
+
+			// assert
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			entries := result.Unreleased.Fixed()
+			if len(entries) != 1 || len(entries[0].Children) != 1 {
+				t.Fatalf("expected a single entry with a single nested entry, but was %v", entries)
+			}
+			expectedDescription := strings.ReplaceAll(description, "\n- ", "\n\\- ")
+			if entries[0].Description != expectedDescription || entries[0].Children[0].Description != expectedDescription {
+				t.Errorf("expected description to be '%s', but was %v", expectedDescription, entries[0])
+			}
+		})
+	}
+}
+
+func TestRenderDoesNotEscapeCompareLinks(t *testing.T) {
+	// arrange
+	provider, err := NewLinkProvider("{repo}/diff?from={from}&to={to}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentChangelog := newChangelog()
+	currentChangelog.URL = "https://git.example.com/project"
+	currentChangelog.Links = provider
+
+	// act
+	result, err := renderFragment("links", currentChangelog)
+
+	// assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[Unreleased]: https://git.example.com/project/diff?from=HEAD&to=HEAD\n"
+	if result != expected {
+		t.Errorf("expected result to be '%s', but was '%s'", expected, result)
+	}
+}
+
+func TestEscapeContinuation(t *testing.T) {
+	testCases := []struct {
+		line           string
+		expectedResult string
+	}{
+		{"that continues here", "that continues here"},
+		{"- that looks like an entry", "\\- that looks like an entry"},
+		{"* that looks like an entry", "\\* that looks like an entry"},
+		{"-that does not", "-that does not"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.line, func(t *testing.T) {
+			result := escapeContinuation(testCase.line)
+
+			if result != testCase.expectedResult {
+				t.Errorf("expected result to be '%s', but was '%s'", testCase.expectedResult, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package changelog
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Template is a user supplied template to render changelogs with, such as one
+// with a house style of section titles or a footer.
+//
+// A template is based on the default Markdown template, so it can use and
+// redefine any of its named parts: "changelog", "header", "title",
+// "description", "unreleased", "release", "release title", "sections",
+// "section", "entry" and "links". A file with content outside of define
+// actions replaces the "changelog" part as a whole. Templates are executed with
+// the Changelog, and can use its methods and those of its releases.
+//
+// Besides the functions of text/template, templates can use:
+//
+//	sections   the sections of a release that have entries
+//	entryLines the lines of an entry as rendered in Markdown
+//	lower      the text in lower case
+//	upper      the text in upper case
+//	join       the texts joined by a separator
+//	replace    the text with all occurrences of old replaced by new
+//	trim       the text without leading and trailing white space
+type Template struct {
+	template *template.Template
+}
+
+// ParseTemplateFile parses the template in the file at the given path.
+func ParseTemplateFile(path string) (*Template, error) {
+	return ParseTemplateFS(os.DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParseTemplateFS parses the templates in the files of the file system that
+// match the given patterns, such as those of an embed.FS. At most one of the
+// files may have content outside of define actions.
+func ParseTemplateFS(fsys fs.FS, patterns ...string) (*Template, error) {
+	result, err := markdownTemplate.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("template pattern %s matches no files", pattern)
+		}
+		files = append(files, matches...)
+	}
+
+	body := ""
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := result.New(file).Parse(string(content)); err != nil {
+			return nil, err
+		}
+
+		if hasContent(result.Lookup(file)) {
+			if body != "" {
+				return nil, fmt.Errorf("templates %s and %s both have content outside of define actions", body, file)
+			}
+			body = file
+		}
+	}
+
+	if body != "" {
+		if _, err := result.AddParseTree("changelog", result.Lookup(body).Tree); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Template{template: result}, nil
+}
+
+// hasContent returns whether the template has content besides white space.
+func hasContent(t *template.Template) bool {
+	if t == nil || t.Tree == nil || t.Tree.Root == nil {
+		return false
+	}
+
+	for _, node := range t.Tree.Root.Nodes {
+		if text, ok := node.(*parse.TextNode); ok && strings.TrimSpace(string(text.Text)) == "" {
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+// RenderOption configures how Render renders a changelog.
+type RenderOption func(options *renderOptions)
+
+type renderOptions struct {
+	template *Template
+}
+
+// WithTemplate makes Render render the changelog with the given template
+// instead of the default Markdown template. The changelog is rendered anew as a
+// whole, even when it was parsed with the Lossless option.
+func WithTemplate(t *Template) RenderOption {
+	return func(options *renderOptions) {
+		options.template = t
+	}
+}
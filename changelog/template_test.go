@@ -0,0 +1,134 @@
+package changelog
+
+import (
+	"embed"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+//go:embed testdata/templates
+var templates embed.FS
+
+func TestRenderWithTemplate(t *testing.T) {
+	testCases := []struct {
+		name           string
+		pattern        string
+		expectedOutput string
+	}{
+		{"redefined part", "testdata/templates/emoji.tmpl", "# Changelog\n\n" +
+			"All notable changes to this project will be documented in this file.\n\n" +
+			"## [Unreleased]\n\n### 🐛 Fixed\n\n- A bug in the `parser` & lexer.\n\n" +
+			"## [1.1.0] - 2021-03-01 [YANKED]\n\n### ✨ Added\n\n- A feature.\n  - With a detail.\n\n### 🔒 Security\n\n- A vulnerability.\n\n" +
+			"## [1.0.0] - 2021-02-01\n\n### ✨ Added\n\n- The first feature.\n\n" +
+			"[Unreleased]: https://gitlab.com/mrombout/gochange/-/compare/v1.1.0...HEAD\n" +
+			"[1.1.0]: https://gitlab.com/mrombout/gochange/-/compare/v1.0.0...v1.1.0\n"},
+		{"layout", "testdata/templates/layout.tmpl", "# CHANGELOG\n\n" +
+			"1.1.0 (yanked): 1 added, 1 security\n" +
+			"1.0.0: 1 added\n\n" +
+			"[Unreleased]: https://gitlab.com/mrombout/gochange/-/compare/v1.1.0...HEAD\n" +
+			"[1.1.0]: https://gitlab.com/mrombout/gochange/-/compare/v1.0.0...v1.1.0\n\n" +
+			"_Generated by gochange._\n"},
+	}
+
+	changelog := parseLosslessTestdata(t, "testdata/export.md")
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			template, err := ParseTemplateFS(templates, testCase.pattern)
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			output := strings.Builder{}
+
+			err = Render(changelog, &output, WithTemplate(template))
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if output.String() != testCase.expectedOutput {
+				t.Errorf("expected output to be\n%s\nbut was\n%s", testCase.expectedOutput, output.String())
+			}
+		})
+	}
+}
+
+func TestParseTemplateFile(t *testing.T) {
+	template, err := ParseTemplateFile("testdata/templates/layout.tmpl")
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	output := strings.Builder{}
+
+	if err := Render(newChangelog(), &output, WithTemplate(template)); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	if !strings.HasPrefix(output.String(), "# CHANGELOG\n") {
+		t.Errorf("expected output to be rendered with the template, but was\n%s", output.String())
+	}
+}
+
+func TestParseTemplateFSErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.tmpl":       {Data: []byte("# A\n")},
+		"b.tmpl":       {Data: []byte("# B\n")},
+		"invalid.tmpl": {Data: []byte("{{if}}")},
+	}
+
+	testCases := []struct {
+		name     string
+		patterns []string
+	}{
+		{"no match", []string{"missing.tmpl"}},
+		{"two bodies", []string{"a.tmpl", "b.tmpl"}},
+		{"invalid syntax", []string{"invalid.tmpl"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := ParseTemplateFS(fsys, testCase.patterns...)
+
+			if err == nil {
+				t.Errorf("expected an error, but was nil")
+			}
+		})
+	}
+}
+
+func TestParseTemplateDoesNotChangeDefaultTemplate(t *testing.T) {
+	if _, err := ParseTemplateFS(templates, "testdata/templates/*.tmpl"); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	result, err := renderFragment("section", Section{Name: Added, Entries: []Entry{{Description: "A feature."}}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "### Added\n\n- A feature.\n\n" {
+		t.Errorf("expected default template to be unchanged, but rendered '%s'", result)
+	}
+}
+
+func TestTemplateFunctions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"functions.tmpl": {Data: []byte(`{{.Title | replace "log" "s" | upper}} {{lower "ADDED"}} [{{trim "  x  "}}] {{join ", " .TagPrefixes}}`)},
+	}
+	template, err := ParseTemplateFS(fsys, "functions.tmpl")
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	output := strings.Builder{}
+
+	err = template.template.ExecuteTemplate(&output, "changelog", struct {
+		Title       string
+		TagPrefixes []string
+	}{"Changelog", []string{"v", "release-"}})
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if output.String() != "CHANGES added [x] v, release-" {
+		t.Errorf("expected output to be 'CHANGES added [x] v, release-', but was '%s'", output.String())
+	}
+}
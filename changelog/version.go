@@ -0,0 +1,264 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Bump is the part of a semantic version that is incremented for a release.
+type Bump int
+
+// The parts of a semantic version that can be incremented, from least to most
+// significant. BumpAuto derives the part from the changes in a release.
+const (
+	BumpAuto Bump = iota - 1
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+// ParseBump parses the name of a bump, one of "auto", "major", "minor" or
+// "patch".
+func ParseBump(name string) (Bump, error) {
+	switch strings.ToLower(name) {
+	case "auto":
+		return BumpAuto, nil
+	case "major":
+		return BumpMajor, nil
+	case "minor":
+		return BumpMinor, nil
+	case "patch":
+		return BumpPatch, nil
+	}
+
+	return BumpAuto, fmt.Errorf("unknown bump '%s', must be one of auto, major, minor or patch", name)
+}
+
+func (b Bump) String() string {
+	switch b {
+	case BumpAuto:
+		return "auto"
+	case BumpMajor:
+		return "major"
+	case BumpMinor:
+		return "minor"
+	}
+
+	return "patch"
+}
+
+// Version is a semantic version as described by https://semver.org/, such as
+// "1.3.0-rc.1". A leading "v" is kept when the version is formatted again.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	PreRelease string
+	Build      string
+
+	prefix string
+}
+
+var versionRegex = regexp.MustCompile(`^(v?)(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+
+// ParseVersion parses a semantic version, optionally prefixed with "v".
+func ParseVersion(name string) (Version, error) {
+	match := versionRegex.FindStringSubmatch(name)
+	if match == nil {
+		return Version{}, fmt.Errorf("'%s' is not a semantic version", name)
+	}
+
+	major, _ := strconv.Atoi(match[2])
+	minor, _ := strconv.Atoi(match[3])
+	patch, _ := strconv.Atoi(match[4])
+
+	return Version{
+		Major:      major,
+		Minor:      minor,
+		Patch:      patch,
+		PreRelease: match[5],
+		Build:      match[6],
+		prefix:     match[1],
+	}, nil
+}
+
+func (v Version) String() string {
+	version := fmt.Sprintf("%s%d.%d.%d", v.prefix, v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		version += "-" + v.PreRelease
+	}
+	if v.Build != "" {
+		version += "+" + v.Build
+	}
+
+	return version
+}
+
+// Compare returns -1, 0 or 1 when v has a lower, the same or a higher
+// precedence than the other version. Build metadata does not affect precedence.
+func (v Version) Compare(other Version) int {
+	for _, parts := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if parts[0] != parts[1] {
+			return compareInts(parts[0], parts[1])
+		}
+	}
+
+	switch {
+	case v.PreRelease == other.PreRelease:
+		return 0
+	case v.PreRelease == "":
+		return 1
+	case other.PreRelease == "":
+		return -1
+	}
+
+	identifiers := strings.Split(v.PreRelease, ".")
+	otherIdentifiers := strings.Split(other.PreRelease, ".")
+	for i := 0; i < len(identifiers) && i < len(otherIdentifiers); i++ {
+		if result := compareIdentifiers(identifiers[i], otherIdentifiers[i]); result != 0 {
+			return result
+		}
+	}
+
+	return compareInts(len(identifiers), len(otherIdentifiers))
+}
+
+// compareIdentifiers compares two pre-release identifiers, where numeric
+// identifiers have a lower precedence than alphanumeric ones.
+func compareIdentifiers(a, b string) int {
+	number, aErr := strconv.Atoi(a)
+	otherNumber, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInts(number, otherNumber)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+// Next returns the version that follows v for the given bump. When pre is not
+// empty, the next version is a pre-release with that identifier, numbered
+// after the previous pre-release with the same identifier, e.g. "1.3.0-rc.2"
+// follows "1.3.0-rc.1".
+//
+// When v is a pre-release itself, the bump only applies when it is more
+// significant than the bump that led to the pre-release, otherwise the next
+// version keeps the version of v without the pre-release.
+func (v Version) Next(bump Bump, pre string) Version {
+	next := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, prefix: v.prefix}
+	if v.PreRelease == "" || bump > v.preReleaseBump() {
+		switch bump {
+		case BumpMajor:
+			next = Version{Major: v.Major + 1, prefix: v.prefix}
+		case BumpMinor:
+			next = Version{Major: v.Major, Minor: v.Minor + 1, prefix: v.prefix}
+		default:
+			next = Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1, prefix: v.prefix}
+		}
+	}
+
+	if pre != "" {
+		number := 1
+		if next.Major == v.Major && next.Minor == v.Minor && next.Patch == v.Patch {
+			identifier, previous, ok := splitPreRelease(v.PreRelease)
+			if ok && identifier == pre {
+				number = previous + 1
+			}
+		}
+		next.PreRelease = fmt.Sprintf("%s.%d", pre, number)
+	}
+
+	return next
+}
+
+// preReleaseBump returns the bump that leads to the version of v from the
+// previous release.
+func (v Version) preReleaseBump() Bump {
+	switch {
+	case v.Patch != 0:
+		return BumpPatch
+	case v.Minor != 0:
+		return BumpMinor
+	}
+
+	return BumpMajor
+}
+
+// splitPreRelease splits a pre-release such as "rc.2" into its identifier and
+// number.
+func splitPreRelease(preRelease string) (string, int, bool) {
+	index := strings.LastIndex(preRelease, ".")
+	if index < 0 {
+		return "", 0, false
+	}
+
+	number, err := strconv.Atoi(preRelease[index+1:])
+	if err != nil {
+		return "", 0, false
+	}
+
+	return preRelease[:index], number, true
+}
+
+// ReleaseBump returns the bump that the changes in the given release call for.
+// Removed functionality and breaking changes call for a major bump, added,
+// changed and deprecated functionality for a minor bump and anything else, such
+// as fixes, for a patch.
+func ReleaseBump(release Release) Bump {
+	bump := BumpPatch
+	for _, section := range release.Sections {
+		if len(section.Entries) == 0 {
+			continue
+		}
+
+		switch section.Name {
+		case Removed:
+			return BumpMajor
+		case Added, Changed, Deprecated:
+			bump = BumpMinor
+		}
+
+		if hasBreaking(section.Entries) {
+			return BumpMajor
+		}
+	}
+
+	return bump
+}
+
+// NextVersion returns the version of the release that follows the latest
+// release of the changelog. With BumpAuto the bump is derived from the
+// unreleased changes. A changelog without releases starts at version 0.0.0.
+func NextVersion(changelog Changelog, bump Bump, pre string) (Version, error) {
+	latest := Version{}
+	if len(changelog.Releases) > 0 {
+		var err error
+		latest, err = ParseVersion(changelog.LatestRelease.Name)
+		if err != nil {
+			return Version{}, err
+		}
+	}
+
+	if bump == BumpAuto {
+		bump = ReleaseBump(changelog.Unreleased)
+	}
+
+	return latest.Next(bump, pre), nil
+}
@@ -0,0 +1,201 @@
+package changelog
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		expectedVersion Version
+	}{
+		{"1.2.3", Version{Major: 1, Minor: 2, Patch: 3}},
+		{"v0.10.0", Version{Minor: 10, prefix: "v"}},
+		{"1.3.0-rc.1", Version{Major: 1, Minor: 3, PreRelease: "rc.1"}},
+		{"1.3.0-beta+exp.sha.5114f85", Version{Major: 1, Minor: 3, PreRelease: "beta", Build: "exp.sha.5114f85"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := ParseVersion(testCase.name)
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if result != testCase.expectedVersion {
+				t.Errorf("expected version to be %#v, but was %#v", testCase.expectedVersion, result)
+			}
+			if result.String() != testCase.name {
+				t.Errorf("expected version to format as '%s', but was '%s'", testCase.name, result.String())
+			}
+		})
+	}
+}
+
+func TestParseVersionInvalid(t *testing.T) {
+	testCases := []string{"HEAD", "1.2", "01.2.3", "1.2.3-", "version 1.2.3"}
+
+	for _, testCase := range testCases {
+		t.Run(testCase, func(t *testing.T) {
+			_, err := ParseVersion(testCase)
+
+			if err == nil {
+				t.Errorf("expected an error, but was nil")
+			}
+		})
+	}
+}
+
+func TestParseBump(t *testing.T) {
+	testCases := []struct {
+		name         string
+		expectedBump Bump
+	}{
+		{"auto", BumpAuto},
+		{"major", BumpMajor},
+		{"Minor", BumpMinor},
+		{"patch", BumpPatch},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := ParseBump(testCase.name)
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if result != testCase.expectedBump {
+				t.Errorf("expected bump to be %v, but was %v", testCase.expectedBump, result)
+			}
+		})
+	}
+
+	if _, err := ParseBump("huge"); err == nil {
+		t.Errorf("expected an error for an unknown bump, but was nil")
+	}
+}
+
+func TestVersionNext(t *testing.T) {
+	testCases := []struct {
+		version         string
+		bump            Bump
+		pre             string
+		expectedVersion string
+	}{
+		{"1.2.3", BumpPatch, "", "1.2.4"},
+		{"1.2.3", BumpMinor, "", "1.3.0"},
+		{"1.2.3", BumpMajor, "", "2.0.0"},
+		{"v1.2.3", BumpMinor, "", "v1.3.0"},
+		{"1.2.3+build.1", BumpPatch, "", "1.2.4"},
+		{"1.2.3", BumpMinor, "rc", "1.3.0-rc.1"},
+		{"1.3.0-rc.1", BumpMinor, "rc", "1.3.0-rc.2"},
+		{"1.3.0-rc.2", BumpPatch, "rc", "1.3.0-rc.3"},
+		{"1.3.0-beta.4", BumpMinor, "rc", "1.3.0-rc.1"},
+		{"1.3.0-rc.2", BumpMinor, "", "1.3.0"},
+		{"1.3.0-rc.2", BumpMajor, "", "2.0.0"},
+		{"1.3.0-rc.2", BumpMajor, "rc", "2.0.0-rc.1"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.version+" "+testCase.bump.String()+" "+testCase.pre, func(t *testing.T) {
+			version, err := ParseVersion(testCase.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := version.Next(testCase.bump, testCase.pre)
+
+			if result.String() != testCase.expectedVersion {
+				t.Errorf("expected next version to be '%s', but was '%s'", testCase.expectedVersion, result.String())
+			}
+		})
+	}
+}
+
+func TestReleaseBump(t *testing.T) {
+	testCases := []struct {
+		name         string
+		sections     []Section
+		expectedBump Bump
+	}{
+		{"no changes", []Section{}, BumpPatch},
+		{"only fixes", []Section{{Name: Fixed, Entries: []Entry{{Description: "A bug."}}}, {Name: Security, Entries: []Entry{{Description: "A hole."}}}}, BumpPatch},
+		{"added", []Section{{Name: Fixed, Entries: []Entry{{Description: "A bug."}}}, {Name: Added, Entries: []Entry{{Description: "A feature."}}}}, BumpMinor},
+		{"deprecated", []Section{{Name: Deprecated, Entries: []Entry{{Description: "A feature."}}}}, BumpMinor},
+		{"removed", []Section{{Name: Added, Entries: []Entry{{Description: "A feature."}}}, {Name: Removed, Entries: []Entry{{Description: "A feature."}}}}, BumpMajor},
+		{"empty removed section", []Section{{Name: Fixed, Entries: []Entry{{Description: "A bug."}}}, {Name: Removed}}, BumpPatch},
+		{"breaking change", []Section{{Name: Fixed, Entries: []Entry{{Description: "**BREAKING** Reject invalid input.", Breaking: true}}}}, BumpMajor},
+		{"nested breaking change", []Section{{Name: Fixed, Entries: []Entry{{Description: "A bug.", Children: []Entry{{Description: "Reject invalid input.", Breaking: true}}}}}}, BumpMajor},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := ReleaseBump(Release{Sections: testCase.sections})
+
+			if result != testCase.expectedBump {
+				t.Errorf("expected bump to be %v, but was %v", testCase.expectedBump, result)
+			}
+		})
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		changelog       Changelog
+		bump            Bump
+		expectedVersion string
+	}{
+		{"first release", newChangelog(), BumpMinor, "0.1.0"},
+		{"auto bump", Changelog{
+			Unreleased:    Release{Sections: []Section{{Name: Added, Entries: []Entry{{Description: "A feature."}}}}},
+			Releases:      []Release{{Name: "1.2.3"}},
+			LatestRelease: Release{Name: "1.2.3"},
+		}, BumpAuto, "1.3.0"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := NextVersion(testCase.changelog, testCase.bump, "")
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if result.String() != testCase.expectedVersion {
+				t.Errorf("expected next version to be '%s', but was '%s'", testCase.expectedVersion, result.String())
+			}
+		})
+	}
+}
+
+func TestNextVersionOfInvalidLatestReleaseReturnsError(t *testing.T) {
+	changelog := Changelog{
+		Releases:      []Release{{Name: "latest"}},
+		LatestRelease: Release{Name: "latest"},
+	}
+
+	_, err := NextVersion(changelog, BumpPatch, "")
+
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "v2.0.0"}
+
+	for i := range ordered {
+		for j := range ordered {
+			t.Run(ordered[i]+" "+ordered[j], func(t *testing.T) {
+				a, _ := ParseVersion(ordered[i])
+				b, _ := ParseVersion(ordered[j])
+
+				result := a.Compare(b)
+
+				if expected := compareInts(i, j); result != expected {
+					t.Errorf("expected comparison to be %d, but was %d", expected, result)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mrombout/gochange/changelog"
+	"github.com/spf13/cobra"
+)
+
+// EntryType is the type of change, which determines the section an entry is
+// added to.
+var EntryType string
+
+// KeepVerb indicates whether to keep a leading verb that repeats the type of
+// change, such as "Fixed" in "Fixed null pointer in parser".
+var KeepVerb bool
+
+// Issues are the issues that the entry refers to, either numbers of issues of
+// the forge or keys of an issue tracker, such as "JIRA-456".
+var Issues []string
+
+// PullRequests are the numbers of the pull requests that the entry refers to.
+var PullRequests []string
+
+// Breaking indicates whether the change is a breaking change.
+var Breaking bool
+
+// Author is the author to attribute the entry to, such as "@octocat", instead
+// of the local git user.
+var Author string
+
+// NoAuthor indicates whether to leave the entry unattributed.
+var NoAuthor bool
+
+var issueKeyRegex = regexp.MustCompile(`^[A-Z][A-Z0-9]*-\d+$`)
+
+// sectionVerbs lists for every standard section the words that a description
+// of a change of that type may start with.
+var sectionVerbs = map[string][]string{
+	changelog.Added:      {"add", "adds", "added"},
+	changelog.Changed:    {"change", "changes", "changed"},
+	changelog.Deprecated: {"deprecate", "deprecates", "deprecated"},
+	changelog.Removed:    {"remove", "removes", "removed"},
+	changelog.Fixed:      {"fix", "fixes", "fixed"},
+	changelog.Security:   {"security"},
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+
+	addCmd.Flags().StringVarP(&EntryType, "type", "t", "", "type of change, one of added, changed, deprecated, removed, fixed or security")
+	addCmd.Flags().BoolVar(&KeepVerb, "keep-verb", false, "keep a leading verb that repeats the type of change")
+	addCmd.Flags().StringSliceVar(&Issues, "issue", nil, "number of an issue or key of a tracker issue the change refers to")
+	addCmd.Flags().StringSliceVar(&PullRequests, "pr", nil, "number of a pull request the change refers to")
+	addCmd.Flags().BoolVar(&Breaking, "breaking", false, "mark the change as a breaking change")
+	addCmd.Flags().StringVar(&Author, "author", "", "handle of the author to attribute the change to, such as @octocat (default is the local git user when it has a handle)")
+	addCmd.Flags().BoolVar(&NoAuthor, "no-author", false, "do not attribute the change to an author")
+	addCmd.MarkFlagRequired("type")
+}
+
+var addCmd = &cobra.Command{
+	Use:   "add --type <type> <change>",
+	Short: "Add an entry to the unreleased changes",
+	Long:  "Adds an entry to the section of the unreleased changes that matches the type of change.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		section, err := sectionOfType(EntryType)
+		if err != nil {
+			return err
+		}
+
+		description := args[0]
+		if !KeepVerb {
+			description = stripVerb(description, section)
+		}
+
+		entry := changelog.NewEntry(description, BreakingMarker)
+		if Breaking {
+			entry.MarkBreaking(BreakingMarker)
+		}
+		author, err := entryAuthor(Author, NoAuthor)
+		if err != nil {
+			return err
+		}
+		if author != "" {
+			entry.AddAuthor(author)
+		}
+		references, err := entryReferences(Issues, PullRequests)
+		if err != nil {
+			return err
+		}
+
+		entry, path, err := addEntry(section, entry, references)
+		if err != nil {
+			return err
+		}
+
+		if path != "" {
+			cmd.Printf("Added \"%s\" to the %s section of the unreleased changes in %s.\n", entry.Description, section, path)
+		} else {
+			cmd.Printf("Added \"%s\" to the %s section of the unreleased changes.\n", entry.Description, section)
+		}
+
+		return nil
+	},
+}
+
+// addEntry adds the entry with the given references to the given section of
+// the unreleased changes, and returns the entry as it was added. When the
+// changelog keeps its unreleased changes in fragments, the entry is written to
+// a new fragment instead and the path of the fragment is returned. The fragment
+// is named after the first issue the entry refers to, which is then left out of
+// its description.
+func addEntry(section string, entry changelog.Entry, references []changelog.Reference) (changelog.Entry, string, error) {
+	path, err := findChangelog()
+	if err != nil {
+		return entry, "", err
+	}
+
+	if usesFragments(path) {
+		name := ""
+		for _, reference := range references {
+			if reference.Kind == changelog.IssueReference && name == "" {
+				name = reference.ID
+				continue
+			}
+			entry.AddReference(reference)
+		}
+		path, err := writeFragment(fragmentsDir(path), name, section, entry)
+
+		return entry, path, err
+	}
+
+	for _, reference := range references {
+		entry.AddReference(reference)
+	}
+
+	return entry, "", updateChangelog(func(currentChangelog *changelog.Changelog) error {
+		currentChangelog.Unreleased.AddEntry(section, entry)
+
+		return nil
+	})
+}
+
+// entryReferences returns the references to the given issues and pull
+// requests, linked as configured for the changelog.
+func entryReferences(issues []string, pullRequests []string) ([]changelog.Reference, error) {
+	if len(issues) == 0 && len(pullRequests) == 0 {
+		return nil, nil
+	}
+
+	file, err := openChangelog()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	currentChangelog, err := readChangelog(file)
+	if err != nil {
+		return nil, err
+	}
+	links := referenceLinks(currentChangelog)
+
+	references := []changelog.Reference{}
+	for _, issue := range issues {
+		kind := changelog.IssueReference
+		switch {
+		case issueKeyRegex.MatchString(issue):
+			kind = changelog.TrackerReference
+		case !issueNumberRegex.MatchString(strings.TrimPrefix(issue, "#")):
+			return nil, fmt.Errorf("issue '%s' is neither a number nor a key such as JIRA-456", issue)
+		}
+		references = append(references, links.Reference(currentChangelog.URL, kind, issue))
+	}
+	for _, pullRequest := range pullRequests {
+		if !issueNumberRegex.MatchString(strings.TrimPrefix(pullRequest, "#")) {
+			return nil, fmt.Errorf("pull request '%s' is not a number", pullRequest)
+		}
+		references = append(references, links.Reference(currentChangelog.URL, changelog.PullRequestReference, pullRequest))
+	}
+
+	return references, nil
+}
+
+// entryAuthor returns the author to attribute the entry to, which is the handle
+// of the given author or else the handle of the local git user, or an empty
+// string when the entry is not attributed because noAuthor is set or the git
+// user has no handle. A git user without a handle is not attributed, since
+// only handles are listed as contributors.
+func entryAuthor(author string, noAuthor bool) (string, error) {
+	if noAuthor {
+		if author != "" {
+			return "", errors.New("give either --author or --no-author, not both")
+		}
+		return "", nil
+	}
+	if author != "" {
+		return authorHandle(author)
+	}
+
+	user, isHandle, err := gitUser()
+	if err != nil || !isHandle {
+		return "", err
+	}
+
+	return "@" + user, nil
+}
+
+// authorHandle returns the handle of the given author, such as "@octocat" for
+// both "octocat" and "@octocat".
+func authorHandle(author string) (string, error) {
+	handle := "@" + strings.TrimPrefix(author, "@")
+	if !changelog.IsHandle(handle) {
+		return "", fmt.Errorf("author '%s' is not a handle such as @octocat", author)
+	}
+
+	return handle, nil
+}
+
+// sectionOfType returns the name of the standard section for the given type of
+// change, regardless of case.
+func sectionOfType(entryType string) (string, error) {
+	for _, section := range changelog.StandardSections {
+		if strings.EqualFold(section, entryType) {
+			return section, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown type of change '%s', must be one of %s", entryType, strings.ToLower(strings.Join(changelog.StandardSections, ", ")))
+}
+
+// sectionOfChange returns the name of the standard section that the first word
+// of the given description refers to.
+func sectionOfChange(description string) (string, bool) {
+	word := firstWord(description)
+	for _, section := range changelog.StandardSections {
+		for _, verb := range sectionVerbs[section] {
+			if strings.EqualFold(word, verb) {
+				return section, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// stripVerb removes the first word of the description when it repeats the given
+// section, and capitalizes the remainder.
+func stripVerb(description string, section string) string {
+	if found, ok := sectionOfChange(description); !ok || found != section {
+		return description
+	}
+
+	remainder := strings.TrimLeft(description, " ")
+	remainder = strings.TrimLeft(remainder[strings.IndexAny(remainder, " :")+1:], " :")
+	if remainder == "" {
+		return description
+	}
+
+	first, size := utf8.DecodeRuneInString(remainder)
+	return string(unicode.ToUpper(first)) + remainder[size:]
+}
+
+// firstWord returns the first word of the description, without any trailing
+// colon.
+func firstWord(description string) string {
+	fields := strings.Fields(description)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.TrimSuffix(fields[0], ":")
+}
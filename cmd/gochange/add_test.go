@@ -0,0 +1,380 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+func TestSectionOfType(t *testing.T) {
+	testCases := []struct {
+		entryType       string
+		expectedSection string
+	}{
+		{"added", changelog.Added},
+		{"Changed", changelog.Changed},
+		{"DEPRECATED", changelog.Deprecated},
+		{"removed", changelog.Removed},
+		{"fixed", changelog.Fixed},
+		{"security", changelog.Security},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.entryType, func(t *testing.T) {
+			result, err := sectionOfType(testCase.entryType)
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if result != testCase.expectedSection {
+				t.Errorf("expected section to be '%s', but was '%s'", testCase.expectedSection, result)
+			}
+		})
+	}
+}
+
+func TestSectionOfType_WhenTypeIsUnknown_ReturnsError(t *testing.T) {
+	// act
+	_, err := sectionOfType("performance")
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestSectionOfChange(t *testing.T) {
+	testCases := []struct {
+		change          string
+		expectedSection string
+		expectedOk      bool
+	}{
+		{"Added links to navigational page.", changelog.Added, true},
+		{"Add links to navigational page.", changelog.Added, true},
+		{"fixed link to navigation page.", changelog.Fixed, true},
+		{"Fix: link to navigation page.", changelog.Fixed, true},
+		{"Security navigational page is not longer a threat.", changelog.Security, true},
+		{"Improved navigational page.", "", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.change, func(t *testing.T) {
+			result, ok := sectionOfChange(testCase.change)
+
+			if ok != testCase.expectedOk || result != testCase.expectedSection {
+				t.Errorf("expected section to be '%s' (%t), but was '%s' (%t)", testCase.expectedSection, testCase.expectedOk, result, ok)
+			}
+		})
+	}
+}
+
+func TestStripVerb(t *testing.T) {
+	testCases := []struct {
+		description         string
+		section             string
+		expectedDescription string
+	}{
+		{"Fixed null pointer in parser", changelog.Fixed, "Null pointer in parser"},
+		{"fix: null pointer in parser", changelog.Fixed, "Null pointer in parser"},
+		{"Null pointer in parser", changelog.Fixed, "Null pointer in parser"},
+		{"Added null pointer in parser", changelog.Fixed, "Added null pointer in parser"},
+		{"Fixed", changelog.Fixed, "Fixed"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			result := stripVerb(testCase.description, testCase.section)
+
+			if result != testCase.expectedDescription {
+				t.Errorf("expected description to be '%s', but was '%s'", testCase.expectedDescription, result)
+			}
+		})
+	}
+}
+
+func TestAdd_WhenIssuesAndPullRequestsAreGiven_AddsReferences(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n\n[Unreleased]: https://github.com/mrombout/gochange/compare/v1.0.0...HEAD\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	addCmd.SetOutput(&bytes.Buffer{})
+	EntryType, Issues, PullRequests = "fixed", []string{"12", "JIRA-456"}, []string{"#34"}
+	defer func() { EntryType, Issues, PullRequests = "", nil, nil }()
+
+	// act
+	err := addCmd.RunE(addCmd, []string{"Null pointer in parser."})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	expected := "- Null pointer in parser. ([#12](https://github.com/mrombout/gochange/issues/12), JIRA-456, [#34](https://github.com/mrombout/gochange/pull/34))\n"
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("expected changelog to contain '%s', but was\n%s", expected, content)
+	}
+}
+
+func TestAdd_WhenDescriptionRefersToIssue_DoesNotReferItAgain(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n\n[Unreleased]: https://github.com/mrombout/gochange/compare/v1.0.0...HEAD\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	addCmd.SetOutput(&bytes.Buffer{})
+	EntryType, Issues, NoAuthor = "removed", []string{"12"}, true
+	defer func() { EntryType, Issues, NoAuthor = "", nil, false }()
+
+	// act
+	err := addCmd.RunE(addCmd, []string{"Dropped foo (#12)"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	if !strings.Contains(string(content), "- Dropped foo (#12)\n") {
+		t.Errorf("expected issue to be referred to once, but was\n%s", content)
+	}
+}
+
+func TestEntryReferences_WhenIssueIsInvalid_ReturnsError(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	_, err := entryReferences([]string{"parser"}, nil)
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestAuthorHandle(t *testing.T) {
+	testCases := []struct {
+		author         string
+		expectedHandle string
+	}{
+		{"octocat", "@octocat"},
+		{"@octocat", "@octocat"},
+		{"@jane.doe", "@jane.doe"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.author, func(t *testing.T) {
+			handle, err := authorHandle(testCase.author)
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if handle != testCase.expectedHandle {
+				t.Errorf("expected handle to be '%s', but was '%s'", testCase.expectedHandle, handle)
+			}
+		})
+	}
+}
+
+func TestAuthorHandle_WhenAuthorIsNotAHandle_ReturnsError(t *testing.T) {
+	// act
+	_, err := authorHandle("Jane Doe")
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestAdd_WhenAuthorIsGiven_AttributesEntry(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	addCmd.SetOutput(&bytes.Buffer{})
+	EntryType, Author = "fixed", "octocat"
+	defer func() { EntryType, Author = "", "" }()
+
+	// act
+	err := addCmd.RunE(addCmd, []string{"Null pointer in parser."})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	if !strings.Contains(string(content), "- Null pointer in parser by @octocat.\n") {
+		t.Errorf("expected entry to be attributed, but was\n%s", content)
+	}
+}
+
+func TestAdd_WhenDescriptionIsAttributedToAuthor_DoesNotAttributeItAgain(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	addCmd.SetOutput(&bytes.Buffer{})
+	EntryType, Author = "added", "alice"
+	defer func() { EntryType, Author = "", "" }()
+
+	// act
+	err := addCmd.RunE(addCmd, []string{"Added bar by @alice"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	if !strings.Contains(string(content), "- Bar by @alice\n") {
+		t.Errorf("expected entry to be attributed once, but was\n%s", content)
+	}
+}
+
+func TestAdd_WhenAuthorIsFollowedByChange_AttributesEntry(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rootCmd.SetArgs([]string{"add", "--type", "added", "--author", "@octocat", "Thing one"})
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	defer func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		EntryType, Author = "", ""
+	}()
+
+	// act
+	err := rootCmd.Execute()
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	if !strings.Contains(string(content), "- Thing one by @octocat\n") {
+		t.Errorf("expected entry to be attributed, but was\n%s", content)
+	}
+}
+
+func TestAdd_WhenNoAuthorIsGiven_AttributesEntryToGitUser(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	testCases := []struct {
+		name          string
+		email         string
+		noAuthor      bool
+		expectedEntry string
+	}{
+		{"git user", "1234+janedoe@users.noreply.github.com", false, "- Null pointer in parser by @janedoe.\n"},
+		{"git user without handle", "jane.doe@example.com", false, "- Null pointer in parser.\n"},
+		{"no author", "1234+janedoe@users.noreply.github.com", true, "- Null pointer in parser.\n"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// arrange
+			chdir(t, t.TempDir())
+			git(t, "init", "--quiet")
+			git(t, "config", "user.name", "Jane Doe")
+			git(t, "config", "user.email", testCase.email)
+			if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			addCmd.SetOutput(&bytes.Buffer{})
+			EntryType, NoAuthor = "fixed", testCase.noAuthor
+			defer func() { EntryType, NoAuthor = "", false }()
+
+			// act
+			err := addCmd.RunE(addCmd, []string{"Null pointer in parser."})
+
+			// assert
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			content, _ := os.ReadFile("CHANGELOG.md")
+			if !strings.Contains(string(content), testCase.expectedEntry) {
+				t.Errorf("expected changelog to contain '%s', but was\n%s", testCase.expectedEntry, content)
+			}
+		})
+	}
+}
+
+func TestEntryAuthor_WhenAuthorAndNoAuthorAreGiven_ReturnsError(t *testing.T) {
+	// act
+	_, err := entryAuthor("octocat", true)
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestAdd_WhenBreakingWithMarker_MarksEntry(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	addCmd.SetOutput(&bytes.Buffer{})
+	EntryType, Breaking, BreakingMarker = "changed", true, "⚠️"
+	defer func() { EntryType, Breaking, BreakingMarker = "", false, "" }()
+
+	// act
+	err := addCmd.RunE(addCmd, []string{"Renamed Parse to Read."})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	if !strings.Contains(string(content), "### Changed\n\n- ⚠️ Renamed Parse to Read.\n") {
+		t.Errorf("expected entry to be marked as breaking, but was\n%s", content)
+	}
+}
+
+func TestAdd_WhenGivenSeveralChanges_ReturnsError(t *testing.T) {
+	// act
+	err := addCmd.Args(addCmd, []string{"Thing one", "Thing two"})
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestAdd_WhenDescriptionIsMarkedBreaking_DoesNotMarkItAgain(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	addCmd.SetOutput(&bytes.Buffer{})
+	EntryType, Breaking, NoAuthor = "removed", true, true
+	defer func() { EntryType, Breaking, NoAuthor = "", false, false }()
+
+	// act
+	err := addCmd.RunE(addCmd, []string{"**BREAKING** Dropped foo."})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	if !strings.Contains(string(content), "### Removed\n\n- **BREAKING** Dropped foo.\n") {
+		t.Errorf("expected entry to be marked as breaking once, but was\n%s", content)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+// changelogFile is the name of the changelog gochange creates by default.
+const changelogFile = "CHANGELOG.md"
+
+// changelogNames are the names of the files that are recognized as changelog
+// when looking for one.
+var changelogNames = []string{changelogFile, "CHANGES.md"}
+
+// ChangelogPath is the path of the changelog to work on. When empty, the
+// nearest changelog is looked up instead.
+var ChangelogPath string
+
+// BreakingMarker is the marker that the descriptions of breaking changes start
+// with, besides "**BREAKING**". New breaking changes are marked with it.
+var BreakingMarker string
+
+// errNoChangelog indicates that there is no changelog to work on.
+var errNoChangelog = errors.New("no changelog found")
+
+// findChangelog returns the path of the changelog to work on. Unless a path is
+// given, it walks up from the working directory to the root of the repository
+// and returns the first changelog it finds.
+func findChangelog() (string, error) {
+	if ChangelogPath != "" {
+		return ChangelogPath, nil
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, name := range changelogNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return "", fmt.Errorf("%w, run 'gochange init' to create one", errNoChangelog)
+}
+
+// openChangelog opens the changelog for reading and writing.
+func openChangelog() (*os.File, error) {
+	path, err := findChangelog()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w at %s, run 'gochange init' to create it", errNoChangelog, path)
+	}
+
+	return file, err
+}
+
+// readChangelog losslessly lexes and parses the changelog stored in the given
+// file. Parse errors are prefixed with the name of the file, so they read as
+// "CHANGELOG.md:42: expected section title, found text line". Without a link
+// provider selected by the flags or stored by init, the compare links are
+// recognised as those of the forge of the origin remote where possible.
+func readChangelog(file *os.File) (changelog.Changelog, error) {
+	tokens, err := changelog.LexReader(file)
+	if err != nil {
+		return changelog.Changelog{}, err
+	}
+
+	provider, err := linkProvider()
+	if err != nil {
+		return changelog.Changelog{}, err
+	}
+
+	detected := provider
+	if detected == nil {
+		detected = originLinkProvider()
+	}
+
+	currentChangelog, err := changelog.Parse(tokens, parseOptions(detected)...)
+	var parseError *changelog.ParseError
+	if errors.As(err, &parseError) {
+		return currentChangelog, fmt.Errorf("%s:%w", relativePath(file.Name()), err)
+	}
+	applyLinks(&currentChangelog, provider)
+
+	return currentChangelog, err
+}
+
+// relativePath returns the given path relative to the working directory, so
+// that it reads as "CHANGELOG.md" rather than as the absolute path found when
+// looking up the changelog. Paths outside the working directory are returned
+// as is.
+func relativePath(path string) string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	relative, err := filepath.Rel(dir, absolute)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return path
+	}
+
+	return relative
+}
+
+// writeChangelog replaces the content of the given file with the rendered
+// changelog. The changelog is rendered before the file is truncated, so that
+// the file is left untouched when rendering fails.
+func writeChangelog(file *os.File, currentChangelog changelog.Changelog) error {
+	rendered := bytes.Buffer{}
+	if err := changelog.Render(currentChangelog, &rendered); err != nil {
+		return err
+	}
+
+	if _, err := file.Seek(0, 0); err != nil {
+		return err
+	}
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+
+	_, err := rendered.WriteTo(file)
+	return err
+}
+
+// createChangelog writes the rendered changelog to the file at the given path,
+// replacing the file if it exists. The changelog is rendered before the file
+// is written, so that an existing file is left untouched when rendering fails.
+func createChangelog(path string, currentChangelog changelog.Changelog) error {
+	rendered := bytes.Buffer{}
+	if err := changelog.Render(currentChangelog, &rendered); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, rendered.Bytes(), 0644)
+}
+
+// updateChangelog reads the changelog, applies the given update to it and
+// writes it back.
+func updateChangelog(update func(currentChangelog *changelog.Changelog) error) error {
+	file, err := openChangelog()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	currentChangelog, err := readChangelog(file)
+	if err != nil {
+		return err
+	}
+
+	if err := update(&currentChangelog); err != nil {
+		return err
+	}
+
+	return writeChangelog(file, currentChangelog)
+}
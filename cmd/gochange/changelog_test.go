@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+// chdir changes the working directory for the duration of the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+}
+
+func TestFindChangelog_WhenPathIsGiven_ReturnsPath(t *testing.T) {
+	// arrange
+	ChangelogPath = "services/billing/CHANGELOG.md"
+	defer func() { ChangelogPath = "" }()
+
+	// act
+	path, err := findChangelog()
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if path != "services/billing/CHANGELOG.md" {
+		t.Errorf("expected path to be '%s', but was '%s'", "services/billing/CHANGELOG.md", path)
+	}
+}
+
+func TestFindChangelog_WhenInSubdirectory_ReturnsNearestChangelog(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	os.Mkdir(filepath.Join(root, ".git"), 0755)
+	os.MkdirAll(filepath.Join(root, "services", "billing", "internal"), 0755)
+	ioutil.WriteFile(filepath.Join(root, "CHANGELOG.md"), []byte{}, 0644)
+	ioutil.WriteFile(filepath.Join(root, "services", "billing", "CHANGES.md"), []byte{}, 0644)
+	chdir(t, filepath.Join(root, "services", "billing", "internal"))
+
+	// act
+	path, err := findChangelog()
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if filepath.Base(filepath.Dir(path)) != "billing" || filepath.Base(path) != "CHANGES.md" {
+		t.Errorf("expected path to be the changelog of the billing service, but was '%s'", path)
+	}
+}
+
+func TestFindChangelog_WhenNoChangelogInRepository_ReturnsError(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	os.Mkdir(filepath.Join(root, ".git"), 0755)
+	os.Mkdir(filepath.Join(root, "docs"), 0755)
+	chdir(t, filepath.Join(root, "docs"))
+
+	// act
+	_, err := findChangelog()
+
+	// assert
+	if !errors.Is(err, errNoChangelog) {
+		t.Errorf("expected error to be '%v', but was '%v'", errNoChangelog, err)
+	}
+}
+
+func TestReadChangelog_WhenParseFails_ReportsRelativePath(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	os.Mkdir(filepath.Join(root, ".git"), 0755)
+	ioutil.WriteFile(filepath.Join(root, "CHANGELOG.md"), []byte("# Changelog\n\nNo releases.\n"), 0644)
+	chdir(t, root)
+	file, err := openChangelog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// act
+	_, err = readChangelog(file)
+
+	// assert
+	var parseError *changelog.ParseError
+	if !errors.As(err, &parseError) {
+		t.Fatalf("expected a parse error, but was '%v'", err)
+	}
+	if err.Error() != "CHANGELOG.md:4: expected release title, found end of file" {
+		t.Errorf("expected error to be 'CHANGELOG.md:4: expected release title, found end of file', but was '%v'", err)
+	}
+}
+
+func TestRelativePath(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	os.Mkdir(filepath.Join(root, "docs"), 0755)
+	chdir(t, filepath.Join(root, "docs"))
+
+	testCases := []struct {
+		path         string
+		expectedPath string
+	}{
+		{filepath.Join(root, "docs", "CHANGELOG.md"), "CHANGELOG.md"},
+		{"CHANGELOG.md", "CHANGELOG.md"},
+		{filepath.Join(root, "CHANGELOG.md"), filepath.Join(root, "CHANGELOG.md")},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.path, func(t *testing.T) {
+			if result := relativePath(testCase.path); result != testCase.expectedPath {
+				t.Errorf("expected path to be '%s', but was '%s'", testCase.expectedPath, result)
+			}
+		})
+	}
+}
+
+// failingLinks is a link provider that cannot create compare links, so that
+// rendering a changelog that uses it fails.
+type failingLinks struct{}
+
+func (failingLinks) CompareURL(repository, from, to string) string {
+	panic("cannot create compare links")
+}
+
+func (failingLinks) ParseCompareURL(url string) (string, string, string, bool) {
+	return "", "", "", false
+}
+
+func TestWriteChangelog_WhenRenderingFails_KeepsFile(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	ioutil.WriteFile(path, []byte("# Changelog\n"), 0644)
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// act
+	err = writeChangelog(file, changelog.Changelog{Title: "Changelog", URL: "https://github.com/mrombout/gochange", Links: failingLinks{}})
+
+	// assert
+	if err == nil {
+		t.Fatalf("expected an error, but was nil")
+	}
+	content, _ := ioutil.ReadFile(path)
+	if string(content) != "# Changelog\n" {
+		t.Errorf("expected changelog to be kept, but was '%s'", content)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(contributorsCmd)
+}
+
+var contributorsCmd = &cobra.Command{
+	Use:   "contributors <version|latest|unreleased>",
+	Short: "List the contributors to a release",
+	Long:  "Lists the authors that the entries of a single release are attributed to, one per line, such as to thank them in the announcement of the release. Use \"latest\" for the latest release and \"unreleased\" for the unreleased changes.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a version, latest or unreleased")
+		}
+
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := openChangelog()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		currentChangelog, err := readChangelog(file)
+		if err != nil {
+			return err
+		}
+
+		release, err := findRelease(currentChangelog, args[0])
+		if err != nil {
+			return err
+		}
+
+		for _, contributor := range release.Contributors() {
+			fmt.Fprintln(cmd.OutOrStdout(), contributor)
+		}
+
+		return nil
+	},
+}
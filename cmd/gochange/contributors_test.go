@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestContributors_WhenEntriesAreAttributed_ListsAuthors(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	content := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Not released by @hubot.
+
+## [1.0.0] - 2021-01-02
+
+### Added
+
+- Invoices by @octocat and @jane (#12).
+
+### Fixed
+
+- Null pointer in parser (@octocat).
+- Crash on empty changelog.
+`
+	if err := os.WriteFile("CHANGELOG.md", []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	contributorsCmd.SetOut(out)
+
+	// act
+	err := contributorsCmd.RunE(contributorsCmd, []string{"latest"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if out.String() != "@octocat\n@jane\n" {
+		t.Errorf("expected contributors to be listed once, but was '%s'", out.String())
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around every change.
+const diffContext = 3
+
+// diffLine is a single line of a diff, prefixed with " ", "-" or "+".
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff returns the changes between before and after as a unified diff of
+// the file at the given path, or an empty string when they are equal. Relative
+// paths are prefixed with "a/" and "b/" in the header, absolute paths are not.
+func unifiedDiff(path string, before string, after string) string {
+	if before == after {
+		return ""
+	}
+
+	lines := diffLines(splitLines(before), splitLines(after))
+	out := strings.Builder{}
+	if filepath.IsAbs(path) {
+		fmt.Fprintf(&out, "--- %s\n+++ %s\n", path, path)
+	} else {
+		fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", filepath.ToSlash(path), filepath.ToSlash(path))
+	}
+
+	oldLine, newLine := 1, 1
+	for start := 0; start < len(lines); {
+		if lines[start].kind == ' ' {
+			oldLine++
+			newLine++
+			start++
+			continue
+		}
+
+		// Grow the hunk until it is followed by more unchanged lines than
+		// fit in the context of two hunks.
+		end := start
+		for unchanged := 0; end < len(lines) && unchanged <= 2*diffContext; end++ {
+			if lines[end].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+		for end > start && lines[end-1].kind == ' ' {
+			end--
+		}
+
+		from := start
+		for from > 0 && start-from < diffContext && lines[from-1].kind == ' ' {
+			from--
+		}
+		to := end
+		for to < len(lines) && to-end < diffContext && lines[to].kind == ' ' {
+			to++
+		}
+
+		oldStart, newStart := oldLine-(start-from), newLine-(start-from)
+		oldCount, newCount := 0, 0
+		hunk := strings.Builder{}
+		for _, line := range lines[from:to] {
+			if line.kind != '+' {
+				oldCount++
+			}
+			if line.kind != '-' {
+				newCount++
+			}
+			fmt.Fprintf(&hunk, "%c%s\n", line.kind, line.text)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n%s", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount), hunk.String())
+
+		for _, line := range lines[start:to] {
+			if line.kind != '+' {
+				oldLine++
+			}
+			if line.kind != '-' {
+				newLine++
+			}
+		}
+		start = to
+	}
+
+	return out.String()
+}
+
+// hunkRange returns the range of lines of a hunk as a unified diff writes it.
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns the shortest edit from a to b. The lines that a and b start
+// and end with alike are left out of the search for the edit, which is found
+// with the algorithm of Myers in time and memory that grow with the number of
+// changed lines rather than with the length of a and b.
+func diffLines(a []string, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b)-prefix-suffix)
+	for _, line := range a[:prefix] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	lines = append(lines, shortestEdit(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', line})
+	}
+
+	return lines
+}
+
+// shortestEdit returns the shortest edit from a to b as found by the algorithm
+// of Myers. For every number of changes d it keeps how far the edits with d
+// changes reach along each diagonal k, where k is the difference between the
+// lines taken from a and those taken from b.
+func shortestEdit(a []string, b []string) []diffLine {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	reach := make([]int, 2*offset+1)
+	trace := [][]int{}
+
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int{}, reach[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			x := reach[offset+k-1] + 1
+			if k == -d || (k != d && reach[offset+k-1] < reach[offset+k+1]) {
+				x = reach[offset+k+1]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			reach[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrackEdit(a, b, trace)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backtrackEdit returns the edit from a to b that ends at their last lines,
+// following back the reach of every number of changes as kept in the trace.
+func backtrackEdit(a []string, b []string, trace [][]int) []diffLine {
+	lines := []diffLine{}
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		previous := trace[d]
+		k := x - y
+		previousK := k - 1
+		if k == -d || (k != d && previous[d+k-1] < previous[d+k+1]) {
+			previousK = k + 1
+		}
+		previousX := previous[d+previousK]
+		previousY := previousX - previousK
+
+		for x > previousX && y > previousY {
+			lines = append(lines, diffLine{' ', a[x-1]})
+			x--
+			y--
+		}
+		if x == previousX {
+			lines = append(lines, diffLine{'+', b[y-1]})
+			y--
+		} else {
+			lines = append(lines, diffLine{'-', a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		lines = append(lines, diffLine{' ', a[x-1]})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+
+	return lines
+}
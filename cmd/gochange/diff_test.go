@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnifiedDiff_WhenTextsAreEqual_ReturnsEmptyString(t *testing.T) {
+	// act
+	result := unifiedDiff("CHANGELOG.md", "a\nb\n", "a\nb\n")
+
+	// assert
+	if result != "" {
+		t.Errorf("expected diff to be empty, but was '%s'", result)
+	}
+}
+
+func TestUnifiedDiff_WhenChangesAreFarApart_ReturnsSeparateHunks(t *testing.T) {
+	// arrange
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n"
+	after := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nM\nn\no\n"
+
+	// act
+	result := unifiedDiff("CHANGELOG.md", before, after)
+
+	// assert
+	expected := "--- a/CHANGELOG.md\n+++ b/CHANGELOG.md\n" +
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+		"@@ -10,5 +10,6 @@\n j\n k\n l\n-m\n+M\n n\n+o\n"
+	if result != expected {
+		t.Errorf("expected diff to be\n%s\nbut was\n%s", expected, result)
+	}
+}
+
+func TestUnifiedDiff_WhenFileIsEmptied_ReturnsRemovedLines(t *testing.T) {
+	// act
+	result := unifiedDiff("CHANGELOG.md", "a\n", "")
+
+	// assert
+	expected := "--- a/CHANGELOG.md\n+++ b/CHANGELOG.md\n@@ -1 +0,0 @@\n-a\n"
+	if result != expected {
+		t.Errorf("expected diff to be\n%s\nbut was\n%s", expected, result)
+	}
+}
+
+func TestUnifiedDiff_WhenPathIsAbsolute_LeavesOutPrefixes(t *testing.T) {
+	// arrange
+	path, err := filepath.Abs("CHANGELOG.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	result := unifiedDiff(path, "a\n", "b\n")
+
+	// assert
+	expected := "--- " + path + "\n+++ " + path + "\n@@ -1 +1 @@\n-a\n+b\n"
+	if result != expected {
+		t.Errorf("expected diff to be\n%s\nbut was\n%s", expected, result)
+	}
+}
+
+func TestDiffLines_ReturnsShortestEdit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        string
+		b        string
+		expected string
+	}{
+		{"inserted", "a\nc", "a\nb\nc", " a+b c"},
+		{"removed", "a\nb\nc", "a\nc", " a-b c"},
+		{"moved", "a\nb\nc\nd", "b\nc\nd\na", "-a b c d+a"},
+		{"replaced", "a\nb\nx\nc", "a\ny\nb\nc", " a+y b-x c"},
+		{"nothing in common", "a\nb", "c", "-a-b+c"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			lines := diffLines(splitLines(testCase.a), splitLines(testCase.b))
+
+			// assert
+			result := ""
+			for _, line := range lines {
+				result += string(line.kind) + line.text
+			}
+			if result != testCase.expected {
+				t.Errorf("expected diff to be '%s', but was '%s'", testCase.expected, result)
+			}
+		})
+	}
+}
+
+func TestDiffLines_WhenChangelogIsLong_DiffsOnlyTheChangedLines(t *testing.T) {
+	// arrange
+	a := make([]string, 100000)
+	for i := range a {
+		a[i] = fmt.Sprintf("- Entry %d.", i)
+	}
+	b := append([]string{}, a...)
+	b[50000] = "- Changed entry."
+
+	// act
+	lines := diffLines(a, b)
+
+	// assert
+	if len(lines) != len(a)+1 || lines[50000].kind != '-' || lines[50001].kind != '+' {
+		t.Errorf("expected one line to be replaced, but was %d lines", len(lines))
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrombout/gochange/changelog"
+	"github.com/spf13/cobra"
+)
+
+// ExportFormatName is the format to export the changelog to, one of "json" or
+// "yaml".
+var ExportFormatName string
+
+// ExportOutput is the path of the file to export the changelog to. The
+// changelog is written to the standard output when empty.
+var ExportOutput string
+
+// ImportFormatName is the format to import the changelog from, one of "json" or
+// "yaml". When empty, it is derived from the extension of the imported file.
+var ImportFormatName string
+
+// ForceImport indicates whether to overwrite the changelog if one already
+// exists.
+var ForceImport bool
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+
+	exportCmd.Flags().StringVar(&ExportFormatName, "format", "json", "format to export to: json or yaml")
+	exportCmd.Flags().StringVarP(&ExportOutput, "output", "o", "", "file to export to (default is the standard output)")
+
+	importCmd.Flags().StringVar(&ImportFormatName, "format", "", "format to import from: json or yaml (default is derived from the extension of the file)")
+	importCmd.Flags().BoolVarP(&ForceImport, "force", "f", false, "overwrite existing changelog if one already exists")
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the changelog as JSON or YAML",
+	Long:  "Exports the changelog as JSON or YAML, following a versioned schema that holds the releases with their dates, yanked flags, sections and entries, and their compare links.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := changelog.ParseExportFormat(ExportFormatName)
+		if err != nil {
+			return err
+		}
+
+		file, err := openChangelog()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		currentChangelog, err := readChangelog(file)
+		if err != nil {
+			return err
+		}
+
+		if ExportOutput == "" {
+			return changelog.Export(currentChangelog, cmd.OutOrStdout(), format)
+		}
+
+		output, err := os.Create(ExportOutput)
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+
+		return changelog.Export(currentChangelog, output, format)
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Create the changelog from JSON or YAML",
+	Long:  "Creates the changelog from JSON or YAML as written by 'gochange export'. Use - to read from the standard input.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a file to import")
+		}
+
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := importFormat(args[0])
+		if err != nil {
+			return err
+		}
+
+		path := ChangelogPath
+		if path == "" {
+			path = changelogFile
+		}
+		if _, err := os.Stat(path); err == nil && !ForceImport {
+			return fmt.Errorf("changelog %s already exists, use --force to overwrite it", path)
+		}
+
+		var input io.Reader = cmd.InOrStdin()
+		if args[0] != "-" {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			input = file
+		}
+
+		importedChangelog, err := changelog.Import(input, format)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		provider, err := linkProvider()
+		if err != nil {
+			return err
+		}
+		applyLinks(&importedChangelog, provider)
+
+		return createChangelog(path, importedChangelog)
+	},
+}
+
+// importFormat returns the format to import the given file from, which is given
+// as flag or derived from the extension of the file. JSON is assumed when
+// neither tells the format.
+func importFormat(path string) (changelog.ExportFormat, error) {
+	if ImportFormatName != "" {
+		return changelog.ParseExportFormat(ImportFormatName)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return changelog.ExportYAML, nil
+	}
+
+	return changelog.ExportJSON, nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+func TestImportFormat(t *testing.T) {
+	testCases := []struct {
+		path           string
+		formatName     string
+		expectedFormat changelog.ExportFormat
+	}{
+		{"changelog.json", "", changelog.ExportJSON},
+		{"changelog.yaml", "", changelog.ExportYAML},
+		{"changelog.YML", "", changelog.ExportYAML},
+		{"-", "", changelog.ExportJSON},
+		{"-", "yaml", changelog.ExportYAML},
+		{"changelog.yaml", "json", changelog.ExportJSON},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.path+" "+testCase.formatName, func(t *testing.T) {
+			ImportFormatName = testCase.formatName
+			t.Cleanup(func() { ImportFormatName = "" })
+
+			format, err := importFormat(testCase.path)
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if format != testCase.expectedFormat {
+				t.Errorf("expected format to be %d, but was %d", testCase.expectedFormat, format)
+			}
+		})
+	}
+}
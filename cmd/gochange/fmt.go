@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mrombout/gochange/changelog"
+	"github.com/spf13/cobra"
+)
+
+// CheckFormat makes fmt only check whether the changelog is formatted, instead
+// of formatting it.
+var CheckFormat bool
+
+// DiffFormat makes fmt print the changes formatting would make, instead of
+// formatting the changelog.
+var DiffFormat bool
+
+var errNotFormatted = errors.New("changelog is not formatted")
+
+func init() {
+	rootCmd.AddCommand(fmtCmd)
+
+	fmtCmd.Flags().BoolVar(&CheckFormat, "check", false, "only check whether the changelog is formatted")
+	fmtCmd.Flags().BoolVar(&DiffFormat, "diff", false, "print the changes instead of writing them")
+}
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt",
+	Short: "Format the changelog",
+	Long:  "Formats the changelog canonically: sections are put in the order of Keep a Changelog, entries use \"-\" bullets, titles are separated by blank lines and the compare links are created anew. Content that gochange does not understand, such as paragraphs in a release, is kept in place.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := openChangelog()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		before, err := io.ReadAll(file)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Seek(0, 0); err != nil {
+			return err
+		}
+
+		currentChangelog, err := readChangelog(file)
+		if err != nil {
+			return err
+		}
+
+		formattedChangelog := changelog.Format(currentChangelog)
+		after := strings.Builder{}
+		if err := changelog.Render(formattedChangelog, &after); err != nil {
+			return err
+		}
+
+		if DiffFormat {
+			fmt.Fprint(cmd.OutOrStdout(), unifiedDiff(relativePath(file.Name()), string(before), after.String()))
+		}
+		if string(before) == after.String() {
+			return nil
+		}
+		if CheckFormat {
+			return fmt.Errorf("%s: %w, run 'gochange fmt' to format it", relativePath(file.Name()), errNotFormatted)
+		}
+		if DiffFormat {
+			return nil
+		}
+
+		return writeChangelog(file, formattedChangelog)
+	},
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFmt_WhenChangelogHasParagraphs_KeepsThem(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	input := "# Changelog\n\n## [Unreleased]\n\n## [1.0.0] - 2021-02-01\nSome words about this release.\n### Added\n* Something.\n"
+	if err := os.WriteFile("CHANGELOG.md", []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	err := fmtCmd.RunE(fmtCmd, []string{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	output, err := os.ReadFile("CHANGELOG.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), "## [1.0.0] - 2021-02-01\n\nSome words about this release.\n\n### Added\n\n- Something.\n") {
+		t.Errorf("expected the paragraph to be kept, but changelog was\n%s", output)
+	}
+}
+
+func TestFmt_WhenDiffIsGiven_PrintsRelativePath(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	input := "# Changelog\n## [Unreleased]\n"
+	if err := os.WriteFile("CHANGELOG.md", []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	DiffFormat = true
+	defer func() { DiffFormat = false }()
+	output := bytes.Buffer{}
+	fmtCmd.SetOut(&output)
+	defer fmtCmd.SetOut(nil)
+
+	// act
+	err := fmtCmd.RunE(fmtCmd, []string{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if !strings.HasPrefix(output.String(), "--- a/CHANGELOG.md\n+++ b/CHANGELOG.md\n") {
+		t.Errorf("expected diff of CHANGELOG.md, but was\n%s", output.String())
+	}
+}
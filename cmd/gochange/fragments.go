@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+// fragmentsDirName is the name of the directory next to the changelog that
+// holds fragments. Unreleased changes are written to fragments instead of to
+// the changelog when this directory exists, so that branches adding changes do
+// not conflict.
+const fragmentsDirName = "changelog.d"
+
+// maxSlugLength is the maximum length of the part of the name of a fragment
+// that is derived from its description.
+const maxSlugLength = 50
+
+// fragment is a single unreleased change stored in its own file, named
+// "<name>.<type>.md" and containing the description of the change. A name that
+// is a number, optionally followed by a counter such as "123-2", refers to the
+// issue that the change resolves.
+type fragment struct {
+	path    string
+	section string
+	entry   changelog.Entry
+	issue   string
+}
+
+var fragmentNameRegex = regexp.MustCompile(`^(.+)\.([a-zA-Z]+)\.md$`)
+var issueNumberRegex = regexp.MustCompile(`^\d+$`)
+var fragmentIssueRegex = regexp.MustCompile(`^(\d+)(?:-\d+)?$`)
+var slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// fragmentsDir returns the fragments directory of the changelog at the given
+// path.
+func fragmentsDir(changelogPath string) string {
+	return filepath.Join(filepath.Dir(changelogPath), fragmentsDirName)
+}
+
+// usesFragments returns whether the changelog at the given path keeps its
+// unreleased changes in fragments.
+func usesFragments(changelogPath string) bool {
+	info, err := os.Stat(fragmentsDir(changelogPath))
+
+	return err == nil && info.IsDir()
+}
+
+// readFragments returns the fragments in the given directory, ordered by name.
+// Files that are not named like fragments, such as a README, are ignored. The
+// entries of the fragments are parsed like those of the changelog, so that
+// breaking changes, references and authors are recognised.
+func readFragments(dir string) ([]fragment, error) {
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fragments := []fragment{}
+	for _, file := range files {
+		match := fragmentNameRegex.FindStringSubmatch(file.Name())
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") || match == nil {
+			continue
+		}
+
+		path := filepath.Join(dir, file.Name())
+		section, err := sectionOfType(match[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		description := strings.TrimSpace(string(content))
+		if description == "" {
+			return nil, fmt.Errorf("%s: fragment has no description", path)
+		}
+		issue := ""
+		if issueMatch := fragmentIssueRegex.FindStringSubmatch(match[1]); issueMatch != nil {
+			issue = issueMatch[1]
+		}
+
+		fragments = append(fragments, fragment{
+			path:    path,
+			section: section,
+			entry:   changelog.NewEntry(description, BreakingMarker),
+			issue:   issue,
+		})
+	}
+
+	return fragments, nil
+}
+
+// writeFragment writes the entry to a new fragment in the given directory, and
+// returns the path of the fragment. The fragment is named after the given name,
+// such as the number of an issue, or after its description if the name is
+// empty.
+func writeFragment(dir string, name string, section string, entry changelog.Entry) (string, error) {
+	slug := name
+	if slug == "" {
+		slug = strings.Trim(slugRegex.ReplaceAllString(strings.ToLower(entry.Description), "-"), "-")
+		if len(slug) > maxSlugLength {
+			slug = strings.TrimRight(slug[:maxSlugLength], "-")
+		}
+	}
+	if slug == "" {
+		slug = "change"
+	}
+
+	entryType := strings.ToLower(section)
+	path := filepath.Join(dir, fmt.Sprintf("%s.%s.md", slug, entryType))
+	for i := 2; ; i++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			path = filepath.Join(dir, fmt.Sprintf("%s-%d.%s.md", slug, i, entryType))
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		_, err = file.WriteString(entry.Description + "\n")
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+
+		return path, err
+	}
+}
+
+// collectFragments adds the fragments to the unreleased changes of the given
+// changelog. The issues that fragments are named after are linked as
+// configured for the changelog.
+func collectFragments(currentChangelog *changelog.Changelog, fragments []fragment) {
+	links := referenceLinks(*currentChangelog)
+	for _, fragment := range fragments {
+		entry := fragment.entry
+		if fragment.issue != "" {
+			entry.AddReference(links.Reference(currentChangelog.URL, changelog.IssueReference, fragment.issue))
+		}
+		currentChangelog.Unreleased.AddEntry(fragment.section, entry)
+	}
+}
+
+// removeFragments deletes the files of the given fragments.
+func removeFragments(fragments []fragment) error {
+	for _, fragment := range fragments {
+		if err := os.Remove(fragment.path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+func TestWriteFragment_WhenFragmentExists_WritesNewFragment(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	entry := changelog.Entry{Description: "Null pointer in parser."}
+
+	// act
+	first, err := writeFragment(dir, "", changelog.Fixed, entry)
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	second, err := writeFragment(dir, "", changelog.Fixed, entry)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if filepath.Base(first) != "null-pointer-in-parser.fixed.md" {
+		t.Errorf("expected first fragment to be 'null-pointer-in-parser.fixed.md', but was '%s'", filepath.Base(first))
+	}
+	if filepath.Base(second) != "null-pointer-in-parser-2.fixed.md" {
+		t.Errorf("expected second fragment to be 'null-pointer-in-parser-2.fixed.md', but was '%s'", filepath.Base(second))
+	}
+}
+
+func TestReadFragments_WhenDirectoryHasFragments_ReturnsFragments(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	files := map[string]string{
+		"123.fixed.md":      "Null pointer in parser.\n",
+		"invoices.added.md": "Invoices.\n",
+		"README.md":         "Put fragments here.\n",
+		".gitkeep":          "",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// act
+	fragments, err := readFragments(dir)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if len(fragments) != 2 {
+		t.Fatalf("expected 2 fragments, but was %d", len(fragments))
+	}
+	if fragments[0].section != changelog.Fixed || fragments[0].entry.Description != "Null pointer in parser." || fragments[0].issue != "123" {
+		t.Errorf("expected fixed fragment referring to issue 123, but was %v", fragments[0])
+	}
+	if fragments[1].section != changelog.Added || fragments[1].entry.Description != "Invoices." {
+		t.Errorf("expected added fragment, but was %v", fragments[1])
+	}
+}
+
+func TestReadFragments_WhenTypeIsUnknown_ReturnsError(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "speed.performance.md"), []byte("Faster.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	_, err := readFragments(dir)
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestAdd_WhenFragmentsDirectoryExists_WritesFragment(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(fragmentsDirName, 0755); err != nil {
+		t.Fatal(err)
+	}
+	addCmd.SetOutput(&bytes.Buffer{})
+	EntryType = "fixed"
+	defer func() { EntryType = "" }()
+
+	// act
+	err := addCmd.RunE(addCmd, []string{"Fixed null pointer in parser."})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, err := os.ReadFile(filepath.Join(fragmentsDirName, "null-pointer-in-parser.fixed.md"))
+	if err != nil {
+		t.Fatalf("expected fragment to be written, but was '%v'", err)
+	}
+	if string(content) != "Null pointer in parser.\n" {
+		t.Errorf("expected fragment to contain the description, but was '%s'", content)
+	}
+	changelogContent, _ := os.ReadFile("CHANGELOG.md")
+	if string(changelogContent) != "# Changelog\n\n## [Unreleased]\n" {
+		t.Errorf("expected changelog to be kept, but was '%s'", changelogContent)
+	}
+}
+
+func TestRelease_WhenFragmentsExist_CollectsAndRemovesFragments(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\nNotable changes.\n\n## [Unreleased]\n\n[Unreleased]: https://github.com/mrombout/gochange/compare/HEAD...HEAD\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(fragmentsDirName, 0755); err != nil {
+		t.Fatal(err)
+	}
+	fragment := filepath.Join(fragmentsDirName, "12.added.md")
+	if err := os.WriteFile(fragment, []byte("Invoices.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	err := releaseCmd.RunE(releaseCmd, []string{"1.0.0"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	if !strings.Contains(string(content), "## [1.0.0] - ") || !strings.Contains(string(content), "### Added\n\n- Invoices. ([#12](https://github.com/mrombout/gochange/issues/12))\n") {
+		t.Errorf("expected fragment to be released, but was\n%s", content)
+	}
+	if _, err := os.Stat(fragment); !os.IsNotExist(err) {
+		t.Errorf("expected fragment to be removed, but it still exists")
+	}
+}
+
+func TestAdd_WhenIssueIsGivenInFragmentMode_NamesFragmentAfterIssue(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n\n[Unreleased]: https://github.com/mrombout/gochange/compare/HEAD...HEAD\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(fragmentsDirName, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(fragmentsDirName, "123.fixed.md"), []byte("Crash on start.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	addCmd.SetOutput(&bytes.Buffer{})
+	EntryType, Issues, PullRequests = "fixed", []string{"123"}, []string{"45"}
+	defer func() { EntryType, Issues, PullRequests = "", nil, nil }()
+
+	// act
+	err := addCmd.RunE(addCmd, []string{"Null pointer in parser."})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, err := os.ReadFile(filepath.Join(fragmentsDirName, "123-2.fixed.md"))
+	if err != nil {
+		t.Fatalf("expected fragment to be named after the issue, but was '%v'", err)
+	}
+	expected := "Null pointer in parser. ([#45](https://github.com/mrombout/gochange/pull/45))\n"
+	if string(content) != expected {
+		t.Errorf("expected fragment to be '%s', but was '%s'", expected, content)
+	}
+	fragments, err := readFragments(fragmentsDirName)
+	if err != nil || len(fragments) != 2 || fragments[1].issue != "123" {
+		t.Errorf("expected fragment to refer to issue 123, but was %v (%v)", fragments, err)
+	}
+}
+
+func TestRelease_WhenFragmentIsBreaking_BumpsMajorVersion(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [Unreleased]\n\n## [1.0.0] - 2021-02-01\n\n### Added\n\n- Invoices.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(fragmentsDirName, 0755); err != nil {
+		t.Fatal(err)
+	}
+	addCmd.SetOutput(&bytes.Buffer{})
+	releaseCmd.SetOutput(&bytes.Buffer{})
+	EntryType, Breaking, BumpName = "changed", true, "auto"
+	defer func() { EntryType, Breaking, BumpName = "", false, "" }()
+	if err := addCmd.RunE(addCmd, []string{"Invoices are sent by email."}); err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	// act
+	err := releaseCmd.RunE(releaseCmd, []string{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	if !strings.Contains(string(content), "## [2.0.0] - ") {
+		t.Errorf("expected breaking fragment to bump the major version, but was\n%s", content)
+	}
+}
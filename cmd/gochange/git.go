@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+var errNoRepository = errors.New("not a git repository")
+
+// findGitDir returns the git directory of the repository that contains the
+// working directory, looking in the working directory and its parents.
+func findGitDir() (string, error) {
+	_, gitDir, err := findRepository()
+
+	return gitDir, err
+}
+
+// findRepository returns the root of the working tree and the git directory of
+// the repository that contains the working directory.
+func findRepository() (string, string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+
+	for {
+		gitDir := filepath.Join(dir, ".git")
+		if info, err := os.Stat(gitDir); err == nil {
+			if info.IsDir() {
+				return dir, gitDir, nil
+			}
+			gitDir, err := readGitFile(gitDir)
+			return dir, gitDir, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", errNoRepository
+		}
+		dir = parent
+	}
+}
+
+// readGitFile returns the git directory that a .git file of a worktree or
+// submodule points to.
+func readGitFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	line := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(line, "gitdir: ") {
+		return "", fmt.Errorf("%s: %w", path, errNoRepository)
+	}
+	gitDir := strings.TrimPrefix(line, "gitdir: ")
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+
+	return gitDir, nil
+}
+
+var configSectionRegex = regexp.MustCompile(`^\[\s*([^\s\]"]+)(?:\s+"([^"]*)")?\s*\]$`)
+
+// remoteURL returns the URL of the remote with the given name from the config
+// of the given git directory.
+func remoteURL(gitDir string, remote string) (string, error) {
+	url, ok, err := configValue(gitDir, "remote", remote, "url")
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("remote %s does not exist", remote)
+	}
+
+	return url, nil
+}
+
+// configValue returns the value of the given key in the given section and
+// subsection of the config of the given git directory, or false if the config
+// does not contain the key.
+func configValue(gitDir string, section string, subsection string, key string) (string, bool, error) {
+	file, err := os.Open(filepath.Join(gitDir, "config"))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	defer file.Close()
+
+	inSection := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if match := configSectionRegex.FindStringSubmatch(line); match != nil {
+			inSection = strings.EqualFold(match[1], section) && match[2] == subsection
+			continue
+		}
+
+		index := strings.Index(line, "=")
+		if inSection && index >= 0 && strings.EqualFold(strings.TrimSpace(line[:index]), key) {
+			value := strings.TrimSpace(line[index+1:])
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				return unquoted, true, nil
+			}
+			return strings.Trim(value, `"`), true, nil
+		}
+	}
+
+	return "", false, scanner.Err()
+}
+
+// setConfigValue sets the given key in the given section and subsection of the
+// config of the given git directory, replacing its value if the key is set
+// already. An empty value removes the key.
+func setConfigValue(gitDir string, section string, subsection string, key string, value string) error {
+	path := filepath.Join(gitDir, "config")
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	lines := []string{}
+	if len(content) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	}
+
+	keyIndex, sectionEnd, inSection := -1, -1, false
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if match := configSectionRegex.FindStringSubmatch(line); match != nil {
+			inSection = strings.EqualFold(match[1], section) && match[2] == subsection
+			if inSection {
+				sectionEnd = i + 1
+			}
+			continue
+		}
+		if !inSection || line == "" {
+			continue
+		}
+
+		sectionEnd = i + 1
+		index := strings.Index(line, "=")
+		if index >= 0 && strings.EqualFold(strings.TrimSpace(line[:index]), key) {
+			keyIndex = i
+		}
+	}
+
+	entry := fmt.Sprintf("\t%s = %s", key, strconv.Quote(value))
+	switch {
+	case keyIndex >= 0 && value == "":
+		lines = append(lines[:keyIndex], lines[keyIndex+1:]...)
+	case keyIndex >= 0:
+		lines[keyIndex] = entry
+	case value == "":
+		return nil
+	case sectionEnd >= 0:
+		lines = append(lines[:sectionEnd], append([]string{entry}, lines[sectionEnd:]...)...)
+	default:
+		header := "[" + section + "]"
+		if subsection != "" {
+			header = fmt.Sprintf("[%s \"%s\"]", section, subsection)
+		}
+		lines = append(lines, header, entry)
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+var gitHubNoReplyEmailRegex = regexp.MustCompile(`^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$`)
+var gitLabNoReplyEmailRegex = regexp.MustCompile(`^(?:\d+-)?([^@]+)@users\.noreply\.gitlab\.com$`)
+
+// gitUser returns the local git user to attribute changes to, and whether it
+// is a handle. That is the account of the private email address of GitHub or
+// GitLab in the user.email from the git config, without the leading @, or else
+// the user.name, which is a handle when it has the shape of one, such as
+// "octocat", and a name otherwise, such as "Jane Doe". An empty string is
+// returned when neither is set.
+func gitUser() (string, bool, error) {
+	name, err := gitConfig("user.name")
+	if err != nil {
+		return "", false, err
+	}
+	email, err := gitConfig("user.email")
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, regex := range []*regexp.Regexp{gitHubNoReplyEmailRegex, gitLabNoReplyEmailRegex} {
+		if match := regex.FindStringSubmatch(email); match != nil {
+			return match[1], true, nil
+		}
+	}
+
+	return name, name != "" && changelog.IsHandle("@"+name), nil
+}
+
+// gitConfig returns the value of the given key in the git config, including
+// the global config, or an empty string if it is not set or git is not
+// installed.
+func gitConfig(key string) (string, error) {
+	output, err := exec.Command("git", "config", "--get", key).Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) && exitError.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+var scpLikeURLRegex = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+var schemeURLRegex = regexp.MustCompile(`^(?:https?|ssh|git|git\+ssh)://(?:[^@/]+@)?([^:/]+)(?::\d+)?/(.+)$`)
+
+// browseURL returns the URL of the web page of the repository with the given
+// remote URL, such as "https://github.com/owner/repo" for both
+// "git@github.com:owner/repo.git" and "https://github.com/owner/repo.git".
+func browseURL(remote string) (string, error) {
+	match := schemeURLRegex.FindStringSubmatch(remote)
+	if match == nil && !strings.Contains(remote, "://") {
+		match = scpLikeURLRegex.FindStringSubmatch(remote)
+	}
+	if match == nil {
+		return "", fmt.Errorf("cannot tell the web page of remote '%s'", remote)
+	}
+
+	path := strings.TrimSuffix(strings.Trim(match[2], "/"), ".git")
+
+	return "https://" + match[1] + "/" + path, nil
+}
+
+// commit is a single commit of the git history.
+type commit struct {
+	Hash    string
+	Message string
+}
+
+// gitLog returns the commits that are reachable from HEAD but not from the
+// given revision, oldest first. All commits are returned when the revision is
+// empty.
+func gitLog(since string) ([]commit, error) {
+	revisions := "HEAD"
+	if since != "" {
+		revisions = since + "..HEAD"
+	}
+
+	output, err := exec.Command("git", "log", "--reverse", "--format=%H%x00%B%x1e", revisions, "--").Output()
+	if err != nil {
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			return nil, fmt.Errorf("cannot read git history of %s: %s", revisions, strings.TrimSpace(string(exitError.Stderr)))
+		}
+		return nil, err
+	}
+
+	commits := []commit{}
+	for _, record := range strings.Split(string(output), "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		index := strings.Index(record, "\x00")
+		if index < 0 {
+			continue
+		}
+		commits = append(commits, commit{
+			Hash:    record[:index],
+			Message: strings.TrimSpace(record[index+1:]),
+		})
+	}
+
+	return commits, nil
+}
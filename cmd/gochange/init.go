@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/mrombout/gochange/changelog"
 	"github.com/spf13/cobra"
@@ -10,33 +12,106 @@ import (
 // ForceInit indicates whether to overwrite the changelog if one already exists.
 var ForceInit bool
 
+// URLInit is the URL of the repository to create the compare links with,
+// instead of the URL derived from the origin remote.
+var URLInit string
+
+// DescriptionInit is the description of the new changelog.
+var DescriptionInit string
+
+// TitleInit is the title of the new changelog.
+var TitleInit string
+
+const defaultDescription = "All notable changes to this project will be documented in this file."
+
 func init() {
 	rootCmd.AddCommand(initCmd)
 
 	initCmd.Flags().BoolVarP(&ForceInit, "force", "f", false, "overwrite existing changelog if one already exists")
+	initCmd.Flags().StringVar(&URLInit, "url", "", "URL of the repository (default is derived from the origin remote)")
+	initCmd.Flags().StringVar(&DescriptionInit, "description", defaultDescription, "description of the changelog")
+	initCmd.Flags().StringVar(&TitleInit, "title", "Changelog", "title of the changelog")
 }
 
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize an empty changelog",
-	Long:  "Generates an empty changelog template.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Println("Initializing changelog...")
+	Long: `Generates an empty changelog template.
+
+The URL of the repository is derived from the origin remote of the git
+repository, unless it is given with --url. Without an origin remote the URL must
+be given.
+
+The link provider, given with --link-provider or --compare-template or derived
+from the URL, is stored in the config of the git repository, so that later
+commands create the same compare links without the flag.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := ChangelogPath
+		if path == "" {
+			path = changelogFile
+		}
+		if _, err := os.Stat(path); err == nil && !ForceInit {
+			return fmt.Errorf("changelog %s already exists, use --force to overwrite it", path)
+		}
+
+		provider, err := linkProvider()
+		if err != nil {
+			return err
+		}
 
-		file, err := os.Create("CHANGELOG.md")
+		url, err := repositoryURL()
 		if err != nil {
-			panic(err)
+			return err
+		}
+		if provider == nil {
+			provider = guessLinkProvider(url)
 		}
 
 		newChangelog := changelog.Changelog{
-			Description: "Lorum ipsum dolor sit amet.",
-			URL:         "http://github.com/",
+			Title:       TitleInit,
+			Description: DescriptionInit,
+			URL:         url,
 			LatestRelease: changelog.Release{
 				Name: "HEAD",
 			},
 		}
-		changelog.Render(newChangelog, file)
+		applyLinks(&newChangelog, provider)
+
+		cmd.Println("Initializing changelog...")
+		if err := createChangelog(path, newChangelog); err != nil {
+			return err
+		}
+		if err := storeLinkProvider(provider); err != nil {
+			return err
+		}
 
 		cmd.Println("Changelog has been initialized.")
+
+		return nil
 	},
 }
+
+// repositoryURL returns the URL of the repository of the new changelog, which
+// is either given as flag or derived from the origin remote. It returns an
+// error when the URL cannot be derived, rather than creating compare links
+// that lead nowhere.
+func repositoryURL() (string, error) {
+	if URLInit != "" {
+		return strings.TrimSuffix(URLInit, "/"), nil
+	}
+
+	gitDir, err := findGitDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot derive the URL of the repository: %w, use --url to give it", err)
+	}
+	remote, err := remoteURL(gitDir, "origin")
+	if err != nil {
+		return "", fmt.Errorf("cannot derive the URL of the repository: %w, use --url to give it", err)
+	}
+	url, err := browseURL(remote)
+	if err != nil {
+		return "", fmt.Errorf("cannot derive the URL of the repository: %w, use --url to give it", err)
+	}
+
+	return url, nil
+}
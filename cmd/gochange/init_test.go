@@ -2,25 +2,144 @@ package main
 
 import (
 	"bytes"
+	"os"
 	"strings"
 	"testing"
 )
 
 func TestRun_WhenNoChangelog_CreatesChangelog(t *testing.T) {
 	// arrange
+	chdir(t, t.TempDir())
 	buf := bytes.Buffer{}
 	initCmd.SetOutput(&buf)
+	URLInit = "https://github.com/mrombout/gochange"
+	defer func() { URLInit = "" }()
 
 	// act
-	initCmd.Run(initCmd, []string{})
+	err := initCmd.RunE(initCmd, []string{})
 
 	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
 	output := buf.String()
 
 	if !strings.Contains(output, "Initializing changelog...") {
-		t.Errorf("output '%s' did not container '%s'", output, "Initializing changelog...")
+		t.Errorf("output '%s' did not contain '%s'", output, "Initializing changelog...")
 	}
 	if !strings.Contains(output, "Changelog has been initialized.") {
+		t.Errorf("output '%s' did not contain '%s'", output, "Changelog has been initialized.")
+	}
+}
+
+func TestRun_WhenChangelogExists_ReturnsError(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	initCmd.SetOutput(&bytes.Buffer{})
+
+	// act
+	err := initCmd.RunE(initCmd, []string{})
+
+	// assert
+	if err == nil {
+		t.Fatalf("expected an error, but was nil")
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	if string(content) != "# Changelog\n" {
+		t.Errorf("expected changelog to be kept, but was '%s'", content)
+	}
+}
+
+func TestRun_WhenChangelogExistsAndForce_OverwritesChangelog(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	initCmd.SetOutput(&bytes.Buffer{})
+	ForceInit, URLInit = true, "https://github.com/mrombout/gochange"
+	defer func() { ForceInit, URLInit = false, "" }()
+
+	// act
+	err := initCmd.RunE(initCmd, []string{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	if !strings.Contains(string(content), "## [Unreleased]") {
+		t.Errorf("expected changelog to be overwritten, but was '%s'", content)
+	}
+}
+
+func TestRun_WhenOriginRemoteExists_UsesRepositoryURL(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	initRepository(t, dir, "[remote \"origin\"]\n\turl = git@gitlab.com:group/project.git\n")
+	chdir(t, dir)
+	initCmd.SetOutput(&bytes.Buffer{})
+	TitleInit, DescriptionInit = "Release notes", "Notable changes."
+	defer func() { TitleInit, DescriptionInit = "Changelog", defaultDescription }()
+
+	// act
+	err := initCmd.RunE(initCmd, []string{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	expectedContent := "# Release notes\n\nNotable changes.\n\n## [Unreleased]\n\n[Unreleased]: https://gitlab.com/group/project/-/compare/HEAD...HEAD\n"
+	if string(content) != expectedContent {
+		t.Errorf("expected changelog to be\n%s\nbut was\n%s", expectedContent, content)
+	}
+}
+
+func TestRun_WhenURLIsGiven_UsesURL(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	initRepository(t, dir, "[remote \"origin\"]\n\turl = git@gitlab.com:group/project.git\n")
+	chdir(t, dir)
+	initCmd.SetOutput(&bytes.Buffer{})
+	URLInit = "https://github.com/mrombout/gochange/"
+	defer func() { URLInit = "" }()
+
+	// act
+	err := initCmd.RunE(initCmd, []string{})
 
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile("CHANGELOG.md")
+	if !strings.Contains(string(content), "[Unreleased]: https://github.com/mrombout/gochange/compare/HEAD...HEAD\n") {
+		t.Errorf("expected changelog to link to the given URL, but was\n%s", content)
+	}
+}
+
+func TestRun_WhenNoOriginRemoteAndNoURL_ReturnsError(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	initRepository(t, dir, "[core]\n\tbare = false\n")
+	chdir(t, dir)
+	output := bytes.Buffer{}
+	initCmd.SetOutput(&output)
+
+	// act
+	err := initCmd.RunE(initCmd, []string{})
+
+	// assert
+	if err == nil || !strings.Contains(err.Error(), "--url") {
+		t.Errorf("expected an error asking for --url, but was '%v'", err)
+	}
+	if strings.Contains(output.String(), "Initializing changelog...") {
+		t.Errorf("expected no progress to be reported, but was '%s'", output.String())
+	}
+	if _, err := os.Stat("CHANGELOG.md"); !os.IsNotExist(err) {
+		t.Errorf("expected no changelog to be created, but it was")
 	}
 }
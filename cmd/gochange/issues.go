@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mrombout/gochange/changelog"
+	"github.com/spf13/cobra"
+)
+
+// IssueKinds are the kinds of references to list, any of "issue", "pr" or
+// "tracker".
+var IssueKinds []string
+
+func init() {
+	rootCmd.AddCommand(issuesCmd)
+
+	issuesCmd.Flags().StringSliceVar(&IssueKinds, "kind", []string{"issue", "tracker"}, "kinds of references to list: issue, pr or tracker")
+}
+
+var issuesCmd = &cobra.Command{
+	Use:   "issues <version|latest|unreleased>",
+	Short: "List the issues that a release refers to",
+	Long:  "Lists the issues that the entries of a single release refer to, one per line together with its link, such as to close them once the release is published. Use \"latest\" for the latest release and \"unreleased\" for the unreleased changes.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a version, latest or unreleased")
+		}
+
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kinds := []changelog.ReferenceKind{}
+		for _, name := range IssueKinds {
+			kind, err := changelog.ParseReferenceKind(name)
+			if err != nil {
+				return err
+			}
+			kinds = append(kinds, kind)
+		}
+
+		file, err := openChangelog()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		currentChangelog, err := readChangelog(file)
+		if err != nil {
+			return err
+		}
+
+		release, err := findRelease(currentChangelog, args[0])
+		if err != nil {
+			return err
+		}
+
+		links := referenceLinks(currentChangelog)
+		for _, reference := range release.References(kinds...) {
+			if reference.URL == "" {
+				reference.URL = links.Reference(currentChangelog.URL, reference.Kind, reference.ID).URL
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", reference.Label(), reference.URL)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestIssues_WhenReleaseHasReferences_ListsIssuesWithLinks(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	content := `# Changelog
+
+## [Unreleased]
+
+## [1.0.0] - 2021-01-02
+
+### Added
+
+- Invoices. (#12, #34)
+
+### Fixed
+
+- Null pointer in parser. ([!56](https://github.com/mrombout/gochange/pull/56), JIRA-456)
+
+[Unreleased]: https://github.com/mrombout/gochange/compare/1.0.0...HEAD
+[1.0.0]: https://github.com/mrombout/gochange/releases/tag/1.0.0
+`
+	if err := os.WriteFile("CHANGELOG.md", []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	issuesCmd.SetOut(out)
+	TrackerTemplate = "https://jira.example.com/browse/{id}"
+	defer func() { TrackerTemplate = "" }()
+
+	// act
+	err := issuesCmd.RunE(issuesCmd, []string{"1.0.0"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	expected := "#12\thttps://github.com/mrombout/gochange/issues/12\n" +
+		"#34\thttps://github.com/mrombout/gochange/issues/34\n" +
+		"JIRA-456\thttps://jira.example.com/browse/JIRA-456\n"
+	if out.String() != expected {
+		t.Errorf("expected output to be '%s', but was '%s'", expected, out.String())
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+// LinkProviderName is the name of the forge to create compare links for, one
+// of "github", "gitlab", "bitbucket" or "gitea".
+var LinkProviderName string
+
+// CompareTemplate is the template to create compare links with for a custom
+// host, such as "{repo}/compare/{from}..{to}".
+var CompareTemplate string
+
+// TagPrefix is the prefix of the tags of releases, such as "v".
+var TagPrefix string
+
+// IssueTemplate is the template of links to issues, such as
+// "{repo}/issues/{id}".
+var IssueTemplate string
+
+// PullRequestTemplate is the template of links to pull requests, such as
+// "{repo}/pull/{id}".
+var PullRequestTemplate string
+
+// TrackerTemplate is the template of links to the issues of an issue tracker,
+// such as "https://jira.example.com/browse/{id}".
+var TrackerTemplate string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&LinkProviderName, "link-provider", "", "forge to create compare links for: github, gitlab, bitbucket or gitea (default is detected from the changelog)")
+	rootCmd.PersistentFlags().StringVar(&CompareTemplate, "compare-template", "", "template of the compare links of a custom host, using {repo}, {from} and {to}")
+	rootCmd.PersistentFlags().StringVar(&TagPrefix, "tag-prefix", "", "prefix of the tags of releases, such as v (default is detected from the changelog)")
+	rootCmd.PersistentFlags().StringVar(&IssueTemplate, "issue-template", "", "template of links to issues, using {repo} and {id} (default is derived from the forge)")
+	rootCmd.PersistentFlags().StringVar(&PullRequestTemplate, "pr-template", "", "template of links to pull requests, using {repo} and {id} (default is derived from the forge)")
+	rootCmd.PersistentFlags().StringVar(&TrackerTemplate, "tracker-template", "", "template of links to the issues of an issue tracker, using {id}")
+}
+
+// linkConfigSection is the section of the git config that init stores the link
+// provider of the changelog in.
+const linkConfigSection = "gochange"
+
+// linkProviderNames lists the names of the link providers of the supported
+// forges.
+var linkProviderNames = []string{"github", "gitlab", "bitbucket", "gitea"}
+
+// linkProvider returns the link provider selected by the flags, or the one
+// stored in the config of the git repository when no flags are given. It
+// returns nil if neither selects a link provider.
+func linkProvider() (changelog.LinkProvider, error) {
+	switch {
+	case CompareTemplate != "":
+		return changelog.NewLinkProvider(CompareTemplate)
+	case LinkProviderName != "":
+		return changelog.LinkProviderByName(LinkProviderName)
+	}
+
+	return configuredLinkProvider()
+}
+
+// configuredLinkProvider returns the link provider stored in the config of the
+// git repository by init, or nil if none is stored or the working directory is
+// not in a git repository.
+func configuredLinkProvider() (changelog.LinkProvider, error) {
+	gitDir, err := findGitDir()
+	if errors.Is(err, errNoRepository) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	template, ok, err := configValue(gitDir, linkConfigSection, "", "compareTemplate")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return changelog.NewLinkProvider(template)
+	}
+
+	name, ok, err := configValue(gitDir, linkConfigSection, "", "linkProvider")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return changelog.LinkProviderByName(name)
+	}
+
+	return nil, nil
+}
+
+// storeLinkProvider stores the given link provider of a new changelog in the
+// config of the git repository, so that later commands create and recognise
+// the same compare links without the flags. Nothing is stored outside a git
+// repository.
+func storeLinkProvider(provider changelog.LinkProvider) error {
+	gitDir, err := findGitDir()
+	if errors.Is(err, errNoRepository) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	template, name := CompareTemplate, ""
+	if template == "" {
+		if name = linkProviderName(provider); name == "" {
+			return nil
+		}
+	}
+
+	if err := setConfigValue(gitDir, linkConfigSection, "", "compareTemplate", template); err != nil {
+		return err
+	}
+	return setConfigValue(gitDir, linkConfigSection, "", "linkProvider", name)
+}
+
+// linkProviderName returns the name of the forge of the given link provider, or
+// an empty string for the link provider of a custom host.
+func linkProviderName(provider changelog.LinkProvider) string {
+	for _, name := range linkProviderNames {
+		if known, _ := changelog.LinkProviderByName(name); known == provider {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// parseOptions returns the options to parse a changelog with, so that the
+// compare links of the given link provider are recognised.
+func parseOptions(provider changelog.LinkProvider) []changelog.ParseOption {
+	options := []changelog.ParseOption{changelog.Lossless()}
+	if provider != nil {
+		options = append(options, changelog.WithLinkProvider(provider))
+	}
+	if BreakingMarker != "" {
+		options = append(options, changelog.WithBreakingMarker(BreakingMarker))
+	}
+
+	return options
+}
+
+// applyLinks sets the link provider and tag prefix of the changelog when they
+// were given as flags, so that its compare links are created anew.
+func applyLinks(currentChangelog *changelog.Changelog, provider changelog.LinkProvider) {
+	if provider != nil {
+		currentChangelog.Links = provider
+	}
+	if TagPrefix != "" {
+		currentChangelog.TagPrefix = TagPrefix
+	}
+}
+
+// referenceLinks returns the links to the references of entries of the given
+// changelog, those of its forge unless they were given as flags.
+func referenceLinks(currentChangelog changelog.Changelog) changelog.ReferenceLinks {
+	links := currentChangelog.ReferenceLinks()
+	if IssueTemplate != "" {
+		links.Issue = IssueTemplate
+	}
+	if PullRequestTemplate != "" {
+		links.PullRequest = PullRequestTemplate
+	}
+	if TrackerTemplate != "" {
+		links.Tracker = TrackerTemplate
+	}
+
+	return links
+}
+
+// guessLinkProvider returns the link provider of the forge that hosts the
+// repository with the given URL, GitHub if the forge is not recognised.
+func guessLinkProvider(url string) changelog.LinkProvider {
+	if provider := forgeLinkProvider(url); provider != nil {
+		return provider
+	}
+
+	return changelog.GitHub
+}
+
+// forgeLinkProvider returns the link provider of the forge that hosts the
+// repository with the given URL, or nil if the forge is not recognised.
+func forgeLinkProvider(url string) changelog.LinkProvider {
+	switch {
+	case strings.Contains(url, "gitlab"):
+		return changelog.GitLab
+	case strings.Contains(url, "bitbucket"):
+		return changelog.Bitbucket
+	case strings.Contains(url, "gitea"), strings.Contains(url, "codeberg.org"):
+		return changelog.Gitea
+	case strings.Contains(url, "github"):
+		return changelog.GitHub
+	}
+
+	return nil
+}
+
+// originLinkProvider returns the link provider of the forge that hosts the
+// origin remote of the git repository, or nil if there is no origin remote or
+// its forge is not recognised. Since Gitea creates the same compare links as
+// GitHub, its changelogs can only be told apart by their host.
+func originLinkProvider() changelog.LinkProvider {
+	gitDir, err := findGitDir()
+	if err != nil {
+		return nil
+	}
+	remote, err := remoteURL(gitDir, "origin")
+	if err != nil {
+		return nil
+	}
+
+	return forgeLinkProvider(remote)
+}
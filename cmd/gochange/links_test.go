@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+func TestApplyLinks_WhenFlagsAreGiven_OverridesDetectedLinks(t *testing.T) {
+	// arrange
+	currentChangelog := changelog.Changelog{Links: changelog.GitHub}
+	LinkProviderName, TagPrefix = "gitlab", "v"
+	defer func() { LinkProviderName, TagPrefix = "", "" }()
+
+	// act
+	provider, err := linkProvider()
+	applyLinks(&currentChangelog, provider)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if currentChangelog.Links != changelog.GitLab {
+		t.Errorf("expected link provider to be GitLab, but was %v", currentChangelog.Links)
+	}
+	if currentChangelog.TagPrefix != "v" {
+		t.Errorf("expected tag prefix to be 'v', but was '%s'", currentChangelog.TagPrefix)
+	}
+}
+
+func TestApplyLinks_WhenNoFlagsAreGiven_KeepsDetectedLinks(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	currentChangelog := changelog.Changelog{Links: changelog.Bitbucket, TagPrefix: "v"}
+
+	// act
+	provider, err := linkProvider()
+	applyLinks(&currentChangelog, provider)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if currentChangelog.Links != changelog.Bitbucket || currentChangelog.TagPrefix != "v" {
+		t.Errorf("expected links to be kept, but was %v with prefix '%s'", currentChangelog.Links, currentChangelog.TagPrefix)
+	}
+}
+
+func TestLinks_WhenInitIsGivenCompareTemplate_LaterCommandsUseIt(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	initRepository(t, dir, "[core]\n\tbare = false\n")
+	chdir(t, dir)
+	initCmd.SetOutput(&bytes.Buffer{})
+	addCmd.SetOutput(&bytes.Buffer{})
+	releaseCmd.SetOutput(&bytes.Buffer{})
+	URLInit, CompareTemplate = "https://git.example.com/p", "{repo}/diff?from={from}&to={to}"
+	err := initCmd.RunE(initCmd, []string{})
+	URLInit, CompareTemplate = "", ""
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+
+	// act
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		EntryType = "added"
+		err := addCmd.RunE(addCmd, []string{"Feature of " + version + "."})
+		EntryType = ""
+		if err != nil {
+			t.Fatalf("expected error to be nil, but was '%v'", err)
+		}
+		if err := releaseCmd.RunE(releaseCmd, []string{version}); err != nil {
+			t.Fatalf("expected error to be nil, but was '%v'", err)
+		}
+	}
+
+	// assert
+	content, _ := os.ReadFile("CHANGELOG.md")
+	expected := "\n[Unreleased]: https://git.example.com/p/diff?from=1.1.0&to=HEAD\n[1.1.0]: https://git.example.com/p/diff?from=1.0.0&to=1.1.0\n"
+	if !strings.HasSuffix(string(content), expected) || strings.Count(string(content), "[Unreleased]:") != 1 {
+		t.Errorf("expected changelog to end with\n%s\nbut was\n%s", expected, content)
+	}
+}
+
+func TestLinks_WhenOriginIsGitea_LinksPullRequestsOfGitea(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	initRepository(t, dir, "[remote \"origin\"]\n\turl = https://codeberg.org/owner/project.git\n")
+	chdir(t, dir)
+	content := "# Changelog\n\n## [Unreleased]\n\n## [1.0.0] - 2021-01-01\n\n### Added\n\n- Invoices.\n\n[Unreleased]: https://codeberg.org/owner/project/compare/1.0.0...HEAD\n"
+	if err := os.WriteFile("CHANGELOG.md", []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	addCmd.SetOutput(&bytes.Buffer{})
+	EntryType, PullRequests = "fixed", []string{"5"}
+	defer func() { EntryType, PullRequests = "", nil }()
+
+	// act
+	err := addCmd.RunE(addCmd, []string{"Null pointer in parser."})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	written, _ := os.ReadFile("CHANGELOG.md")
+	if !strings.Contains(string(written), "- Null pointer in parser. ([#5](https://codeberg.org/owner/project/pulls/5))\n") {
+		t.Errorf("expected pull request to be linked to Gitea, but was\n%s", written)
+	}
+	if !strings.Contains(string(written), "[Unreleased]: https://codeberg.org/owner/project/compare/1.0.0...HEAD\n") {
+		t.Errorf("expected compare link to be kept, but was\n%s", written)
+	}
+}
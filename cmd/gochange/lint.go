@@ -0,0 +1,246 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrombout/gochange/changelog"
+	"github.com/spf13/cobra"
+)
+
+// LintFormat is the format to report lint issues in, one of "text", "json",
+// "github" or "gitlab".
+var LintFormat string
+
+// EnabledRules are the only lint rules to check, when given.
+var EnabledRules []string
+
+// DisabledRules are the lint rules not to check.
+var DisabledRules []string
+
+var errLintIssues = errors.New("changelog has lint issues")
+
+// lintFormats maps the names of the formats of lint issues to their writer.
+var lintFormats = map[string]func(out io.Writer, path string, issues []changelog.LintIssue) error{
+	"text":   writeTextIssues,
+	"json":   writeJSONIssues,
+	"github": writeGitHubIssues,
+	"gitlab": writeGitLabIssues,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringVar(&LintFormat, "format", "text", "format of the issues: text, json, github or gitlab")
+	lintCmd.Flags().StringSliceVar(&EnabledRules, "enable", nil, "only check the given rules")
+	lintCmd.Flags().StringSliceVar(&DisabledRules, "disable", nil, "do not check the given rules")
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the changelog against the conventions of Keep a Changelog",
+	Long:  "Checks the changelog against the conventions of Keep a Changelog, and exits with a non-zero exit code when it does not follow them.\n\nRules:\n" + describeLintRules(),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		write, ok := lintFormats[LintFormat]
+		if !ok {
+			return fmt.Errorf("unknown format '%s', must be one of text, json, github or gitlab", LintFormat)
+		}
+		rules, err := selectLintRules(EnabledRules, DisabledRules)
+		if err != nil {
+			return err
+		}
+
+		file, err := openChangelog()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		currentChangelog, err := readChangelog(file)
+		if err != nil {
+			return err
+		}
+
+		issues, err := changelog.Lint(currentChangelog, rules...)
+		if err != nil {
+			return err
+		}
+		if err := write(cmd.OutOrStdout(), repositoryPath(file.Name()), issues); err != nil {
+			return err
+		}
+		if len(issues) > 0 {
+			return fmt.Errorf("%w, found %d", errLintIssues, len(issues))
+		}
+
+		return nil
+	},
+}
+
+// describeLintRules returns a line for every lint rule with its name and
+// description.
+func describeLintRules() string {
+	lines := []string{}
+	for _, rule := range changelog.LintRules {
+		lines = append(lines, fmt.Sprintf("  %-18s %s", rule.Name, rule.Description))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// selectLintRules returns the names of the lint rules to check, given the
+// enabled and disabled rules.
+func selectLintRules(enabled []string, disabled []string) ([]string, error) {
+	if len(enabled) == 0 {
+		for _, rule := range changelog.LintRules {
+			enabled = append(enabled, rule.Name)
+		}
+	}
+
+	rules := []string{}
+	for _, name := range enabled {
+		if !isLintRule(name) {
+			return nil, fmt.Errorf("unknown lint rule '%s'", name)
+		}
+		if !contains(disabled, name) {
+			rules = append(rules, name)
+		}
+	}
+	for _, name := range disabled {
+		if !isLintRule(name) {
+			return nil, fmt.Errorf("unknown lint rule '%s'", name)
+		}
+	}
+	if len(rules) == 0 {
+		return nil, errors.New("all lint rules are disabled")
+	}
+
+	return rules, nil
+}
+
+func isLintRule(name string) bool {
+	for _, rule := range changelog.LintRules {
+		if rule.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// repositoryPath returns the path of the changelog relative to the root of the
+// repository, as annotations expect it, or the path itself when it is not part
+// of a repository.
+func repositoryPath(path string) string {
+	root, _, err := findRepository()
+	if err != nil {
+		return path
+	}
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	relative, err := filepath.Rel(root, absolute)
+	if err != nil || strings.HasPrefix(relative, "..") {
+		return path
+	}
+
+	return filepath.ToSlash(relative)
+}
+
+func writeTextIssues(out io.Writer, path string, issues []changelog.LintIssue) error {
+	for _, issue := range issues {
+		if _, err := fmt.Fprintf(out, "%s:%d: %s (%s)\n", path, issue.Line, issue.Message, issue.Rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonIssue is a lint issue as it is written in the json format.
+type jsonIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func writeJSONIssues(out io.Writer, path string, issues []changelog.LintIssue) error {
+	result := []jsonIssue{}
+	for _, issue := range issues {
+		result = append(result, jsonIssue{File: path, Line: issue.Line, Rule: issue.Rule, Message: issue.Message})
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// writeGitHubIssues writes the issues as workflow commands, that GitHub Actions
+// show as annotations.
+func writeGitHubIssues(out io.Writer, path string, issues []changelog.LintIssue) error {
+	escape := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	escapeProperty := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+	for _, issue := range issues {
+		if _, err := fmt.Fprintf(out, "::error file=%s,line=%d,title=%s::%s\n", escapeProperty.Replace(path), issue.Line, escapeProperty.Replace(issue.Rule), escape.Replace(issue.Message)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// gitLabIssue is a lint issue as it is written in a GitLab Code Quality
+// report.
+type gitLabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitLabLocation `json:"location"`
+}
+
+type gitLabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitLabLines `json:"lines"`
+}
+
+type gitLabLines struct {
+	Begin int `json:"begin"`
+}
+
+// writeGitLabIssues writes the issues as a GitLab Code Quality report, that
+// merge requests show as annotations.
+func writeGitLabIssues(out io.Writer, path string, issues []changelog.LintIssue) error {
+	result := []gitLabIssue{}
+	for _, issue := range issues {
+		fingerprint := md5.Sum([]byte(fmt.Sprintf("%s:%d:%s:%s", path, issue.Line, issue.Rule, issue.Message)))
+		result = append(result, gitLabIssue{
+			Description: issue.Message,
+			CheckName:   issue.Rule,
+			Fingerprint: fmt.Sprintf("%x", fingerprint),
+			Severity:    "minor",
+			Location:    gitLabLocation{Path: path, Lines: gitLabLines{Begin: issue.Line}},
+		})
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+var lintIssues = []changelog.LintIssue{
+	{Rule: "date-format", Line: 8, Message: "release 1.0.0 has no date"},
+	{Rule: "empty-section", Line: 12, Message: "section Fixed of release 0.9.0 has no entries"},
+}
+
+func TestSelectLintRules_WhenNoRulesAreEnabled_SelectsAllButDisabledRules(t *testing.T) {
+	// act
+	rules, err := selectLintRules(nil, []string{"compare-link", "link-chain"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if len(rules) != len(changelog.LintRules)-2 {
+		t.Errorf("expected %d rules, but was %v", len(changelog.LintRules)-2, rules)
+	}
+	for _, rule := range rules {
+		if rule == "compare-link" || rule == "link-chain" {
+			t.Errorf("expected rule %s to be disabled, but it was selected", rule)
+		}
+	}
+}
+
+func TestSelectLintRules_WhenRulesAreEnabled_SelectsOnlyEnabledRules(t *testing.T) {
+	// act
+	rules, err := selectLintRules([]string{"date-format", "date-order"}, []string{"date-order"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if len(rules) != 1 || rules[0] != "date-format" {
+		t.Errorf("expected rules to be [date-format], but was %v", rules)
+	}
+}
+
+func TestSelectLintRules_WhenRuleIsUnknown_ReturnsError(t *testing.T) {
+	// act
+	_, err := selectLintRules(nil, []string{"no-such-rule"})
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestWriteTextIssues_WritesIssuePerLine(t *testing.T) {
+	// arrange
+	out := &bytes.Buffer{}
+
+	// act
+	err := writeTextIssues(out, "CHANGELOG.md", lintIssues)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	expected := "CHANGELOG.md:8: release 1.0.0 has no date (date-format)\n" +
+		"CHANGELOG.md:12: section Fixed of release 0.9.0 has no entries (empty-section)\n"
+	if out.String() != expected {
+		t.Errorf("expected output to be '%s', but was '%s'", expected, out.String())
+	}
+}
+
+func TestWriteGitHubIssues_WritesErrorAnnotations(t *testing.T) {
+	// arrange
+	out := &bytes.Buffer{}
+
+	// act
+	err := writeGitHubIssues(out, "docs/CHANGELOG.md", lintIssues[:1])
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	expected := "::error file=docs/CHANGELOG.md,line=8,title=date-format::release 1.0.0 has no date\n"
+	if out.String() != expected {
+		t.Errorf("expected output to be '%s', but was '%s'", expected, out.String())
+	}
+}
+
+func TestWriteGitLabIssues_WritesCodeQualityReport(t *testing.T) {
+	// arrange
+	out := &bytes.Buffer{}
+
+	// act
+	err := writeGitLabIssues(out, "CHANGELOG.md", lintIssues)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	report := []gitLabIssue{}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("expected report to be valid JSON, but was '%v'", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("expected 2 issues, but was %d", len(report))
+	}
+	if report[1].CheckName != "empty-section" || report[1].Location.Path != "CHANGELOG.md" || report[1].Location.Lines.Begin != 12 {
+		t.Errorf("expected second issue to be reported at CHANGELOG.md:12 for empty-section, but was %+v", report[1])
+	}
+	if report[0].Fingerprint == "" || report[0].Fingerprint == report[1].Fingerprint {
+		t.Errorf("expected issues to have unique fingerprints, but were '%s' and '%s'", report[0].Fingerprint, report[1].Fingerprint)
+	}
+}
@@ -1,15 +1,29 @@
 package main
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/mrombout/gochange/changelog"
 	"github.com/spf13/cobra"
 )
 
+// Exit codes of gochange, so that scripts can tell failures apart.
+const (
+	exitError       = 1
+	exitNoChangelog = 2
+	exitParseError  = 3
+	exitIOError     = 4
+	exitLintIssues  = 5
+	exitUnformatted = 6
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&ChangelogPath, "file", "", "path of the changelog (default is the nearest CHANGELOG.md or CHANGES.md)")
+	rootCmd.PersistentFlags().StringVar(&BreakingMarker, "breaking-marker", "", "marker that the descriptions of breaking changes start with (default **BREAKING**)")
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "gochange [change]",
 	Short: "Gochange helps updating changelogs.",
@@ -17,61 +31,48 @@ var rootCmd = &cobra.Command{
 	Args: func(cmd *cobra.Command, args []string) error {
 		return nil
 	},
-	Run: func(cmd *cobra.Command, args []string) {
-		file, err := os.OpenFile("CHANGELOG.md", os.O_RDWR, 0644)
-		if err != nil {
-			panic(err)
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return cmd.Help()
 		}
-		scanner := bufio.NewScanner(file)
+		change := args[0]
 
-		tokens, err := changelog.Lex(scanner)
-		if err != nil {
-			panic(err)
+		section, ok := sectionOfChange(change)
+		if !ok {
+			return fmt.Errorf("cannot tell the type of change from '%s', use 'gochange add --type <type>' instead", firstWord(change))
 		}
 
-		currentChangelog, err := changelog.Parse(tokens)
-		if err != nil {
-			panic(err)
-		}
+		_, _, err := addEntry(section, changelog.Entry{
+			Description: change,
+		}, nil)
 
-		change := args[0]
+		return err
+	},
+}
 
-		switch {
-		case strings.HasPrefix(change, "Added"):
-			currentChangelog.Unreleased.Added = append(currentChangelog.Unreleased.Added, changelog.Entry{
-				Description: change,
-			})
-		case strings.HasPrefix(change, "Changed"):
-			currentChangelog.Unreleased.Changed = append(currentChangelog.Unreleased.Changed, changelog.Entry{
-				Description: change,
-			})
-		case strings.HasPrefix(change, "Deprecated"):
-			currentChangelog.Unreleased.Deprecated = append(currentChangelog.Unreleased.Deprecated, changelog.Entry{
-				Description: change,
-			})
-		case strings.HasPrefix(change, "Removed"):
-			currentChangelog.Unreleased.Removed = append(currentChangelog.Unreleased.Removed, changelog.Entry{
-				Description: change,
-			})
-		case strings.HasPrefix(change, "Fixed"):
-			currentChangelog.Unreleased.Fixed = append(currentChangelog.Unreleased.Fixed, changelog.Entry{
-				Description: change,
-			})
-		case strings.HasPrefix(change, "Security"):
-			currentChangelog.Unreleased.Security = append(currentChangelog.Unreleased.Security, changelog.Entry{
-				Description: change,
-			})
-		}
+// exitCode returns the exit code that gochange exits with for the given error.
+func exitCode(err error) int {
+	var parseError *changelog.ParseError
+	var pathError *os.PathError
+	switch {
+	case errors.Is(err, errNoChangelog):
+		return exitNoChangelog
+	case errors.Is(err, errLintIssues):
+		return exitLintIssues
+	case errors.Is(err, errNotFormatted):
+		return exitUnformatted
+	case errors.As(err, &parseError):
+		return exitParseError
+	case errors.As(err, &pathError):
+		return exitIOError
+	}
 
-		file.Seek(0, 0)
-		file.Truncate(0)
-		changelog.Render(currentChangelog, file)
-	},
+	return exitError
 }
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
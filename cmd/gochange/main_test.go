@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+// TestMain runs the tests without the global git config, so that entries are
+// not attributed to the git user running the tests.
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "gochange")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_CONFIG_HOME", home)
+	os.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(home, ".gitconfig"))
+	os.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+func TestExitCode(t *testing.T) {
+	testCases := []struct {
+		name             string
+		err              error
+		expectedExitCode int
+	}{
+		{"no changelog", fmt.Errorf("%w, run 'gochange init'", errNoChangelog), exitNoChangelog},
+		{"parse error", fmt.Errorf("CHANGELOG.md:%w", &changelog.ParseError{Line: 42, Column: 1}), exitParseError},
+		{"i/o error", &os.PathError{Op: "write", Path: "CHANGELOG.md", Err: errors.New("disk full")}, exitIOError},
+		{"lint issues", fmt.Errorf("%w, found 2", errLintIssues), exitLintIssues},
+		{"not formatted", fmt.Errorf("CHANGELOG.md: %w", errNotFormatted), exitUnformatted},
+		{"other error", errors.New("requires at least one argument"), exitError},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := exitCode(testCase.err)
+
+			if result != testCase.expectedExitCode {
+				t.Errorf("expected exit code to be %d, but was %d", testCase.expectedExitCode, result)
+			}
+		})
+	}
+}
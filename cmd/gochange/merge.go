@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrombout/gochange/changelog"
+	"github.com/spf13/cobra"
+)
+
+// mergeDriverName is the name the merge driver is configured with in git.
+const mergeDriverName = "gochange"
+
+var errConflictsRemain = errors.New("conflicts remain in the changelog")
+
+func init() {
+	rootCmd.AddCommand(mergeDriverCmd)
+	rootCmd.AddCommand(installMergeDriverCmd)
+}
+
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <base> <ours> <theirs>",
+	Short: "Merge two versions of a changelog",
+	Long: `Merges the changes that ours and theirs made to the base version of a
+changelog, and writes the result to ours. Meant to be run by git as the merge
+driver of the changelog, see install-merge-driver.
+
+Entries are merged per section and release. When both sides changed the same
+entry in different ways, the versions are merged line by line instead, leaving
+conflict markers for the conflicting lines.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 3 {
+			return errors.New("requires the base, ours and theirs versions")
+		}
+
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := mergeChangelogs(args[0], args[1], args[2])
+
+		var conflict *changelog.MergeConflict
+		var parseError *changelog.ParseError
+		if errors.As(err, &conflict) || errors.As(err, &parseError) {
+			cmd.PrintErrf("Cannot merge the changelog by entry, %v.\n", err)
+			return mergeLines(args[0], args[1], args[2])
+		}
+
+		return err
+	},
+}
+
+var installMergeDriverCmd = &cobra.Command{
+	Use:   "install-merge-driver",
+	Short: "Configure git to merge the changelog with gochange",
+	Long:  "Configures gochange as the merge driver of the changelog in .gitattributes and the config of the git repository.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, gitDir, err := findRepository()
+		if err != nil {
+			return err
+		}
+
+		pattern := changelogFile
+		if path, err := findChangelog(); err == nil {
+			if absolute, err := filepath.Abs(path); err == nil {
+				if relative, err := filepath.Rel(root, absolute); err == nil {
+					pattern = filepath.ToSlash(relative)
+				}
+			}
+		}
+
+		attribute := fmt.Sprintf("%s merge=%s", pattern, mergeDriverName)
+		if err := appendMissingLine(filepath.Join(root, ".gitattributes"), attribute); err != nil {
+			return err
+		}
+
+		_, configured, err := configValue(gitDir, "merge", mergeDriverName, "driver")
+		if err != nil {
+			return err
+		}
+		if !configured {
+			config := fmt.Sprintf("[merge \"%s\"]\n\tname = gochange changelog merge driver\n\tdriver = gochange merge-driver %%O %%A %%B", mergeDriverName)
+			if err := appendText(filepath.Join(gitDir, "config"), config); err != nil {
+				return err
+			}
+		}
+
+		cmd.Printf("Merge driver is configured for %s.\n", pattern)
+
+		return nil
+	},
+}
+
+// mergeChangelogs merges the changelogs at the given paths, and writes the
+// result to ours unless the changelogs conflict.
+func mergeChangelogs(basePath, oursPath, theirsPath string) error {
+	changelogs := []changelog.Changelog{}
+	for _, path := range []string{basePath, oursPath, theirsPath} {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		currentChangelog, err := readChangelog(file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+		changelogs = append(changelogs, currentChangelog)
+	}
+
+	merged, err := changelog.Merge3(changelogs[0], changelogs[1], changelogs[2])
+	if err != nil {
+		return err
+	}
+
+	return createChangelog(oursPath, merged)
+}
+
+// mergeLines merges the files at the given paths line by line with git, and
+// writes the result to ours including conflict markers.
+func mergeLines(basePath, oursPath, theirsPath string) error {
+	command := exec.Command("git", "merge-file", "-L", "ours", "-L", "base", "-L", "theirs", oursPath, basePath, theirsPath)
+	command.Stderr = os.Stderr
+	err := command.Run()
+
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) && exitError.ExitCode() > 0 {
+		return errConflictsRemain
+	}
+
+	return err
+}
+
+// appendMissingLine appends the line to the file at the given path, unless the
+// file already contains it.
+func appendMissingLine(path string, line string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, existing := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(existing) == line {
+			return nil
+		}
+	}
+
+	return appendText(path, line)
+}
+
+// appendText appends the text as new lines to the file at the given path.
+func appendText(path string, text string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		text = "\n" + text
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.WriteString(text + "\n")
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
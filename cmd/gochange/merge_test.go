@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const mergeBase = `# Changelog
+
+Notable changes.
+
+## [Unreleased]
+
+### Added
+
+- Base.
+
+[Unreleased]: https://github.com/mrombout/gochange/compare/HEAD...HEAD
+`
+
+// writeVersions writes the base, ours and theirs versions of a changelog to dir
+// and returns their paths.
+func writeVersions(t *testing.T, dir string, versions ...string) []string {
+	t.Helper()
+
+	paths := []string{}
+	for i, version := range versions {
+		path := filepath.Join(dir, []string{"base.md", "ours.md", "theirs.md"}[i])
+		if err := os.WriteFile(path, []byte(version), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func TestMergeChangelogs_WhenBothAddEntries_WritesMergedChangelog(t *testing.T) {
+	// arrange
+	paths := writeVersions(t, t.TempDir(),
+		mergeBase,
+		strings.Replace(mergeBase, "- Base.\n", "- Base.\n- Ours.\n", 1),
+		strings.Replace(mergeBase, "- Base.\n", "- Base.\n\n### Fixed\n\n- Theirs.\n", 1))
+
+	// act
+	err := mergeChangelogs(paths[0], paths[1], paths[2])
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	content, _ := os.ReadFile(paths[1])
+	expectedContent := strings.Replace(mergeBase, "- Base.\n", "- Base.\n- Ours.\n\n### Fixed\n\n- Theirs.\n", 1)
+	if string(content) != expectedContent {
+		t.Errorf("expected merged changelog to be\n%s\nbut was\n%s", expectedContent, content)
+	}
+}
+
+func TestMergeDriver_WhenBothEditTheSameEntry_WritesConflictMarkers(t *testing.T) {
+	// arrange
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	paths := writeVersions(t, t.TempDir(),
+		mergeBase,
+		strings.Replace(mergeBase, "- Base.", "- Ours.", 1),
+		strings.Replace(mergeBase, "- Base.", "- Theirs.", 1))
+	mergeDriverCmd.SetErr(&bytes.Buffer{})
+
+	// act
+	err := mergeDriverCmd.RunE(mergeDriverCmd, paths)
+
+	// assert
+	if !errors.Is(err, errConflictsRemain) {
+		t.Fatalf("expected error to be '%v', but was '%v'", errConflictsRemain, err)
+	}
+	content, _ := os.ReadFile(paths[1])
+	if !strings.Contains(string(content), "<<<<<<< ours\n- Ours.\n=======\n- Theirs.\n>>>>>>> theirs\n") {
+		t.Errorf("expected conflict markers, but was\n%s", content)
+	}
+}
+
+func TestInstallMergeDriver_WhenRunTwice_ConfiguresDriverOnce(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	initRepository(t, dir, "[core]\n\tbare = false")
+	chdir(t, dir)
+	if err := os.WriteFile(".gitattributes", []byte("*.go text"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	installMergeDriverCmd.SetOutput(&bytes.Buffer{})
+
+	// act
+	for i := 0; i < 2; i++ {
+		if err := installMergeDriverCmd.RunE(installMergeDriverCmd, []string{}); err != nil {
+			t.Fatalf("expected error to be nil, but was '%v'", err)
+		}
+	}
+
+	// assert
+	attributes, _ := os.ReadFile(".gitattributes")
+	if string(attributes) != "*.go text\nCHANGELOG.md merge=gochange\n" {
+		t.Errorf("expected attribute to be added once, but was\n%s", attributes)
+	}
+	config, _ := os.ReadFile(filepath.Join(".git", "config"))
+	expectedConfig := "[core]\n\tbare = false\n[merge \"gochange\"]\n\tname = gochange changelog merge driver\n\tdriver = gochange merge-driver %O %A %B\n"
+	if string(config) != expectedConfig {
+		t.Errorf("expected config to be\n%s\nbut was\n%s", expectedConfig, config)
+	}
+}
@@ -1,9 +1,8 @@
 package main
 
 import (
-	"bufio"
 	"errors"
-	"os"
+	"fmt"
 	"time"
 
 	"github.com/mrombout/gochange/changelog"
@@ -13,62 +12,159 @@ import (
 // Force indicates whether to overwrite an existing release if one already exists.
 var Force bool
 
-// Merge indiacates whether to merge with an existing release if one already exists.
+// AllowEmpty indicates whether to release even when there are no unreleased
+// changes.
+var AllowEmpty bool
+
+// Merge indicates whether to merge with an existing release if one already exists.
 var Merge bool
 
+// BumpName is the part of the version to increment when no version is given,
+// one of "auto", "major", "minor" or "patch".
+var BumpName string
+
+// PreRelease is the identifier of the pre-release to create, such as "rc".
+var PreRelease string
+
 func init() {
 	rootCmd.AddCommand(releaseCmd)
 
-	releaseCmd.Flags().BoolVarP(&Force, "force", "f", false, "overwrite an existing release if it already exists")
+	releaseCmd.Flags().StringVarP(&BumpName, "bump", "b", "", "increment the latest version instead of giving one: auto, major, minor or patch")
+	releaseCmd.Flags().StringVar(&PreRelease, "pre", "", "release a pre-release with the given identifier, such as rc")
+
+	releaseCmd.Flags().BoolVarP(&Force, "force", "f", false, "overwrite existing release if it already exists")
+	releaseCmd.Flags().BoolVar(&AllowEmpty, "allow-empty", false, "release even when there are no unreleased changes")
 	releaseCmd.Flags().BoolVarP(&Merge, "merge", "m", false, "merge with existing release if it already exists")
 }
 
 var releaseCmd = &cobra.Command{
-	Use:   "release <version>",
+	Use:   "release [version]",
 	Short: "Move all unreleased changes to a release",
-	Long:  "Moves all unreleased changes to a release.",
+	Long: `Moves all unreleased changes to a release.
+
+Instead of giving a version, the latest version can be incremented with --bump.
+An automatic bump is major when something was removed or an entry is marked as
+breaking, minor when something was added, changed or deprecated and patch
+otherwise.
+
+Fragments in the changelog.d directory next to the changelog are added to the
+release, and deleted once the release was written. Releasing fails when there
+are no unreleased changes, unless --allow-empty is given.`,
 	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) < 1 {
-			return errors.New("requires at least one argument")
+		if len(args) < 1 && BumpName == "" {
+			return errors.New("requires a version or --bump")
+		}
+		if len(args) > 0 && BumpName != "" {
+			return errors.New("requires either a version or --bump, not both")
+		}
+		if len(args) > 0 && PreRelease != "" {
+			return errors.New("requires --bump to release a pre-release with --pre")
+		}
+		if Force && Merge {
+			return errors.New("requires either --force or --merge, not both")
 		}
-
-		// parse changelog
-		// move unreleased to release
-		// write changelog
 
 		return nil
 	},
-	Run: func(cmd *cobra.Command, args []string) {
-		file, err := os.OpenFile("CHANGELOG.md", os.O_RDWR, 0644)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := findChangelog()
 		if err != nil {
-			panic(err)
+			return err
 		}
-		scanner := bufio.NewScanner(file)
-
-		tokens, err := changelog.Lex(scanner)
+		fragments, err := readFragments(fragmentsDir(path))
 		if err != nil {
-			panic(err)
+			return err
 		}
 
-		currentChangelog, err := changelog.Parse(tokens)
+		name := ""
+		err = updateChangelog(func(currentChangelog *changelog.Changelog) error {
+			collectFragments(currentChangelog, fragments)
+			if !hasEntries(currentChangelog.Unreleased) && !AllowEmpty {
+				return errors.New("there are no unreleased changes to release, use --allow-empty to release anyway")
+			}
+
+			name, err = releaseName(*currentChangelog, args)
+			if err != nil {
+				return err
+			}
+
+			return releaseChanges(currentChangelog, name, time.Now().Format("2006-01-02"))
+		})
 		if err != nil {
-			panic(err)
+			return err
 		}
+		cmd.Printf("Released version %s.\n", name)
 
-		newRelease := currentChangelog.Unreleased
-		newRelease.Name = args[0]
-		newRelease.Date = time.Now().Format("2006-01-02")
+		return removeFragments(fragments)
+	},
+}
+
+// hasEntries returns whether any section of the release has entries.
+func hasEntries(release changelog.Release) bool {
+	for _, section := range release.Sections {
+		if len(section.Entries) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
 
+// releaseChanges moves the unreleased changes to a new release with the given
+// name and date. When the release already exists it is overwritten if Force is
+// set, or the unreleased changes are merged into it if Merge is set.
+func releaseChanges(currentChangelog *changelog.Changelog, name string, date string) error {
+	newRelease := currentChangelog.Unreleased
+	newRelease.Name = name
+	newRelease.Date = date
+
+	index := -1
+	for i := range currentChangelog.Releases {
+		if currentChangelog.Releases[i].Name == name {
+			index = i
+			break
+		}
+	}
+
+	switch {
+	case index < 0:
 		if len(currentChangelog.Releases) > 0 {
 			newRelease.PreviousRelease = &currentChangelog.Releases[0]
 		}
-
 		currentChangelog.Releases = append([]changelog.Release{newRelease}, currentChangelog.Releases...)
+		index = 0
+	case Force:
+		newRelease.PreviousRelease = currentChangelog.Releases[index].PreviousRelease
+		currentChangelog.Releases[index] = newRelease
+	case Merge:
+		currentChangelog.Releases[index].Merge(newRelease)
+	default:
+		return fmt.Errorf("release %s already exists, use --force to overwrite it or --merge to merge with it", name)
+	}
+
+	if index == 0 {
+		currentChangelog.LatestRelease = currentChangelog.Releases[0]
+	}
+	currentChangelog.Unreleased = changelog.Release{}
+
+	return nil
+}
 
-		currentChangelog.Unreleased = changelog.Release{}
-
-		file.Seek(0, 0)
-		file.Truncate(0)
-		changelog.Render(currentChangelog, file)
-	},
+// releaseName returns the name of the new release, either the version given as
+// argument or the latest version incremented as requested by the flags.
+func releaseName(currentChangelog changelog.Changelog, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	bump, err := changelog.ParseBump(BumpName)
+	if err != nil {
+		return "", err
+	}
+	version, err := changelog.NextVersion(currentChangelog, bump, PreRelease)
+	if err != nil {
+		return "", fmt.Errorf("cannot bump latest release: %w", err)
+	}
+
+	return version.String(), nil
 }
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+func TestReleaseName_WhenBumpIsAuto_IncrementsLatestVersion(t *testing.T) {
+	// arrange
+	currentChangelog := changelog.Changelog{
+		Unreleased: changelog.Release{Sections: []changelog.Section{
+			{Name: changelog.Added, Entries: []changelog.Entry{{Description: "A feature."}}},
+		}},
+		Releases:      []changelog.Release{{Name: "1.2.3"}},
+		LatestRelease: changelog.Release{Name: "1.2.3"},
+	}
+	BumpName, PreRelease = "auto", "rc"
+	defer func() { BumpName, PreRelease = "", "" }()
+
+	// act
+	name, err := releaseName(currentChangelog, nil)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if name != "1.3.0-rc.1" {
+		t.Errorf("expected release name to be '1.3.0-rc.1', but was '%s'", name)
+	}
+}
+
+func TestReleaseName_WhenVersionIsGiven_ReturnsVersion(t *testing.T) {
+	// act
+	name, err := releaseName(changelog.Changelog{}, []string{"2.0.0"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if name != "2.0.0" {
+		t.Errorf("expected release name to be '2.0.0', but was '%s'", name)
+	}
+}
+
+func releasedChangelog() changelog.Changelog {
+	return changelog.Changelog{
+		Unreleased: changelog.Release{Sections: []changelog.Section{
+			{Name: changelog.Fixed, Entries: []changelog.Entry{{Description: "A bug."}, {Description: "Another bug."}}},
+		}},
+		Releases: []changelog.Release{
+			{Name: "1.2.0", Date: "2021-01-01", Sections: []changelog.Section{
+				{Name: changelog.Fixed, Entries: []changelog.Entry{{Description: "A bug."}}},
+			}},
+			{Name: "1.1.0", Date: "2020-01-01"},
+		},
+		LatestRelease: changelog.Release{Name: "1.2.0"},
+	}
+}
+
+func TestReleaseChanges_WhenReleaseDoesNotExist_AddsRelease(t *testing.T) {
+	// arrange
+	currentChangelog := releasedChangelog()
+
+	// act
+	err := releaseChanges(&currentChangelog, "1.3.0", "2022-01-01")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if len(currentChangelog.Releases) != 3 || currentChangelog.Releases[0].Name != "1.3.0" {
+		t.Fatalf("expected release 1.3.0 to be added, but releases were %v", currentChangelog.Releases)
+	}
+	if currentChangelog.Releases[0].PreviousRelease.Name != "1.2.0" {
+		t.Errorf("expected previous release to be '1.2.0', but was '%s'", currentChangelog.Releases[0].PreviousRelease.Name)
+	}
+	if currentChangelog.LatestRelease.Name != "1.3.0" {
+		t.Errorf("expected latest release to be '1.3.0', but was '%s'", currentChangelog.LatestRelease.Name)
+	}
+	if len(currentChangelog.Unreleased.Sections) != 0 {
+		t.Errorf("expected unreleased changes to be empty, but was %v", currentChangelog.Unreleased.Sections)
+	}
+}
+
+func TestReleaseChanges_WhenReleaseExists_ReturnsError(t *testing.T) {
+	// arrange
+	currentChangelog := releasedChangelog()
+
+	// act
+	err := releaseChanges(&currentChangelog, "1.2.0", "2022-01-01")
+
+	// assert
+	if err == nil {
+		t.Fatalf("expected an error, but was nil")
+	}
+	if len(currentChangelog.Releases) != 2 {
+		t.Errorf("expected no release to be added, but releases were %v", currentChangelog.Releases)
+	}
+	if len(currentChangelog.Unreleased.Fixed()) != 2 {
+		t.Errorf("expected unreleased changes to be kept, but was %v", currentChangelog.Unreleased.Sections)
+	}
+}
+
+func TestReleaseChanges_WhenReleaseExistsAndForce_OverwritesRelease(t *testing.T) {
+	// arrange
+	currentChangelog := releasedChangelog()
+	Force = true
+	defer func() { Force = false }()
+
+	// act
+	err := releaseChanges(&currentChangelog, "1.2.0", "2022-01-01")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if len(currentChangelog.Releases) != 2 {
+		t.Fatalf("expected release to be overwritten, but releases were %v", currentChangelog.Releases)
+	}
+	release := currentChangelog.Releases[0]
+	if release.Date != "2022-01-01" {
+		t.Errorf("expected date to be '2022-01-01', but was '%s'", release.Date)
+	}
+	if len(release.Fixed()) != 2 || release.Fixed()[1].Description != "Another bug." {
+		t.Errorf("expected fixed entries to be replaced, but was %v", release.Fixed())
+	}
+	if currentChangelog.LatestRelease.Date != "2022-01-01" {
+		t.Errorf("expected latest release to be overwritten, but was %v", currentChangelog.LatestRelease)
+	}
+}
+
+func TestReleaseChanges_WhenReleaseExistsAndMerge_MergesEntries(t *testing.T) {
+	// arrange
+	currentChangelog := releasedChangelog()
+	Merge = true
+	defer func() { Merge = false }()
+
+	// act
+	err := releaseChanges(&currentChangelog, "1.2.0", "2022-01-01")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if len(currentChangelog.Releases) != 2 {
+		t.Fatalf("expected release to be merged, but releases were %v", currentChangelog.Releases)
+	}
+	release := currentChangelog.Releases[0]
+	if release.Date != "2021-01-01" {
+		t.Errorf("expected date to be kept, but was '%s'", release.Date)
+	}
+	fixed := release.Fixed()
+	if len(fixed) != 2 || fixed[0].Description != "A bug." || fixed[1].Description != "Another bug." {
+		t.Errorf("expected fixed entries to be merged without duplicates, but was %v", fixed)
+	}
+	if len(currentChangelog.Unreleased.Sections) != 0 {
+		t.Errorf("expected unreleased changes to be empty, but was %v", currentChangelog.Unreleased.Sections)
+	}
+}
+
+func TestRelease_WhenThereAreNoUnreleasedChanges_ReturnsError(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	content := "# Changelog\n\n## [Unreleased]\n\n## [2.0.0] - 2021-01-01\n\n### Added\n\n- Invoices.\n"
+	if err := os.WriteFile("CHANGELOG.md", []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	BumpName = "auto"
+	defer func() { BumpName = "" }()
+
+	// act
+	err := releaseCmd.RunE(releaseCmd, []string{})
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+	written, _ := os.ReadFile("CHANGELOG.md")
+	if string(written) != content {
+		t.Errorf("expected changelog to be kept, but was\n%s", written)
+	}
+}
+
+func TestRelease_WhenThereAreNoUnreleasedChangesAndAllowEmpty_ReleasesVersion(t *testing.T) {
+	// arrange
+	chdir(t, t.TempDir())
+	content := "# Changelog\n\n## [Unreleased]\n\n## [2.0.0] - 2021-01-01\n\n### Added\n\n- Invoices.\n"
+	if err := os.WriteFile("CHANGELOG.md", []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	releaseCmd.SetOut(out)
+	defer releaseCmd.SetOut(nil)
+	BumpName, AllowEmpty = "auto", true
+	defer func() { BumpName, AllowEmpty = "", false }()
+
+	// act
+	err := releaseCmd.RunE(releaseCmd, []string{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if out.String() != "Released version 2.0.1.\n" {
+		t.Errorf("expected output to be 'Released version 2.0.1.', but was '%s'", out.String())
+	}
+}
+
+func TestRelease_WhenVersionAndPreReleaseAreGiven_ReturnsError(t *testing.T) {
+	// arrange
+	PreRelease = "rc"
+	defer func() { PreRelease = "" }()
+
+	// act
+	err := releaseCmd.Args(releaseCmd, []string{"1.3.0"})
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
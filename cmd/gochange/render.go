@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mrombout/gochange/changelog"
+	"github.com/spf13/cobra"
+)
+
+// RenderFormat is the format to render the changelog in, one of "markdown" or
+// "html".
+var RenderFormat string
+
+// RenderTemplate is the path of a template to render the changelog with,
+// instead of the default Markdown template.
+var RenderTemplate string
+
+// RenderOutput is the path of the file to render the changelog to. The
+// changelog is written to the standard output when empty.
+var RenderOutput string
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+
+	renderCmd.Flags().StringVar(&RenderFormat, "format", "markdown", "format to render the changelog in: markdown or html")
+	renderCmd.Flags().StringVar(&RenderTemplate, "template", "", "template to render the changelog with, instead of the default Markdown template")
+	renderCmd.Flags().StringVarP(&RenderOutput, "output", "o", "", "file to render the changelog to (default is the standard output)")
+}
+
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render the changelog as Markdown or HTML",
+	Long:  "Renders the changelog as Markdown, or as a standalone HTML page with a table of contents, anchors for every release and compare links. A template in the syntax of Go's text/template package can be given to render Markdown in another layout.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		render, err := renderer(RenderFormat, RenderTemplate)
+		if err != nil {
+			return err
+		}
+
+		file, err := openChangelog()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		currentChangelog, err := readChangelog(file)
+		if err != nil {
+			return err
+		}
+
+		if RenderOutput == "" {
+			return render(currentChangelog, cmd.OutOrStdout())
+		}
+
+		output, err := os.Create(RenderOutput)
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+
+		return render(currentChangelog, output)
+	},
+}
+
+// renderer returns the function that renders a changelog in the format with
+// the given name, using the template at the given path when it is not empty.
+func renderer(format string, templatePath string) (func(changelog.Changelog, io.Writer) error, error) {
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		options := []changelog.RenderOption{}
+		if templatePath != "" {
+			template, err := changelog.ParseTemplateFile(templatePath)
+			if err != nil {
+				return nil, err
+			}
+			options = append(options, changelog.WithTemplate(template))
+		}
+		return func(currentChangelog changelog.Changelog, writer io.Writer) error {
+			return changelog.Render(currentChangelog, writer, options...)
+		}, nil
+	case "html":
+		if templatePath != "" {
+			return nil, errors.New("a template can only be used to render markdown")
+		}
+		return changelog.RenderHTML, nil
+	}
+
+	return nil, fmt.Errorf("unknown format '%s', must be one of markdown or html", format)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+func TestRenderer_WhenFormatIsKnown_ReturnsRenderer(t *testing.T) {
+	for _, format := range []string{"markdown", "md", "HTML"} {
+		t.Run(format, func(t *testing.T) {
+			render, err := renderer(format, "")
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if render == nil {
+				t.Errorf("expected a renderer, but was nil")
+			}
+		})
+	}
+}
+
+func TestRenderer_WhenFormatIsUnknown_ReturnsError(t *testing.T) {
+	// act
+	_, err := renderer("pdf", "")
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestRenderer_WhenTemplateIsGiven_RendersWithTemplate(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "changelog.tmpl")
+	if err := os.WriteFile(path, []byte("{{.Title}} of {{.URL}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	render, err := renderer("markdown", path)
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	output := &bytes.Buffer{}
+
+	// act
+	err = render(changelog.Changelog{Title: "Changes", URL: "https://github.com/mrombout/gochange"}, output)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if output.String() != "Changes of https://github.com/mrombout/gochange\n" {
+		t.Errorf("expected output to be rendered with the template, but was '%s'", output.String())
+	}
+}
+
+func TestRenderer_WhenTemplateIsGivenForHTML_ReturnsError(t *testing.T) {
+	// act
+	_, err := renderer("html", "changelog.tmpl")
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mrombout/gochange/changelog"
+	"github.com/spf13/cobra"
+)
+
+// ShowFormat is the format to print the release in, one of "markdown", "text",
+// "json" or "html".
+var ShowFormat string
+
+// OmitTitle leaves out the title of the release, printing only its sections.
+var OmitTitle bool
+
+// GroupBreaking prints the breaking changes of the release in a section of
+// their own, before all other sections.
+var GroupBreaking bool
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().StringVar(&ShowFormat, "format", "markdown", "format of the release: markdown, text, json or html")
+	showCmd.Flags().BoolVar(&OmitTitle, "no-title", false, "print only the sections of the release, without its title")
+	showCmd.Flags().BoolVar(&GroupBreaking, "group-breaking", false, "print the breaking changes in a section of their own, before all other sections")
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show <version|latest|unreleased>",
+	Short: "Print the changes of a single release",
+	Long:  "Prints the changes of a single release, such as the notes to publish with a release on a forge. Use \"latest\" for the latest release and \"unreleased\" for the unreleased changes.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a version, latest or unreleased")
+		}
+
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := changelog.ParseReleaseFormat(ShowFormat)
+		if err != nil {
+			return err
+		}
+
+		file, err := openChangelog()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		currentChangelog, err := readChangelog(file)
+		if err != nil {
+			return err
+		}
+
+		release, err := findRelease(currentChangelog, args[0])
+		if err != nil {
+			return err
+		}
+
+		return changelog.RenderRelease(release, cmd.OutOrStdout(), changelog.ReleaseOptions{
+			Format:        format,
+			OmitTitle:     OmitTitle,
+			GroupBreaking: GroupBreaking,
+		})
+	},
+}
+
+// findRelease returns the release with the given name, which may also be given
+// as its tag. The names "latest" and "unreleased" refer to the latest release
+// and the unreleased changes.
+func findRelease(currentChangelog changelog.Changelog, name string) (changelog.Release, error) {
+	switch strings.ToLower(name) {
+	case "unreleased":
+		return currentChangelog.Unreleased, nil
+	case "latest":
+		if len(currentChangelog.Releases) == 0 {
+			return changelog.Release{}, errors.New("there is no release yet")
+		}
+		return currentChangelog.Releases[0], nil
+	}
+
+	for _, release := range currentChangelog.Releases {
+		if release.Name == name || currentChangelog.Tag(release.Name) == name {
+			return release, nil
+		}
+	}
+
+	return changelog.Release{}, fmt.Errorf("release %s does not exist", name)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+func TestFindRelease(t *testing.T) {
+	currentChangelog := changelog.Changelog{
+		TagPrefix:  "v",
+		Unreleased: changelog.Release{Name: "Unreleased"},
+		Releases:   []changelog.Release{{Name: "1.1.0"}, {Name: "1.0.0"}},
+	}
+
+	testCases := []struct {
+		name         string
+		expectedName string
+	}{
+		{"unreleased", "Unreleased"},
+		{"latest", "1.1.0"},
+		{"1.0.0", "1.0.0"},
+		{"v1.0.0", "1.0.0"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			release, err := findRelease(currentChangelog, testCase.name)
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, but was '%v'", err)
+			}
+			if release.Name != testCase.expectedName {
+				t.Errorf("expected release to be '%s', but was '%s'", testCase.expectedName, release.Name)
+			}
+		})
+	}
+}
+
+func TestFindRelease_WhenReleaseDoesNotExist_ReturnsError(t *testing.T) {
+	// arrange
+	currentChangelog := changelog.Changelog{Releases: []changelog.Release{{Name: "1.0.0"}}}
+
+	// act
+	_, err := findRelease(currentChangelog, "2.0.0")
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestFindRelease_WhenThereAreNoReleases_ReturnsErrorForLatest(t *testing.T) {
+	// act
+	_, err := findRelease(changelog.Changelog{}, "latest")
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
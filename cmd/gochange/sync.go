@@ -0,0 +1,123 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mrombout/gochange/changelog"
+	"github.com/spf13/cobra"
+)
+
+// commitSections maps the types of Conventional Commits to the section their
+// changes are added to. Commits of other types are not added, unless they are
+// breaking changes.
+var commitSections = map[string]string{
+	"feat": changelog.Added,
+	"fix":  changelog.Fixed,
+	"perf": changelog.Changed,
+}
+
+var conventionalCommitRegex = regexp.MustCompile(`^(\w+)(?:\([^)]*\))?(!)?: (.+)$`)
+
+func init() {
+	rootCmd.AddCommand(syncCommitsCmd)
+}
+
+var syncCommitsCmd = &cobra.Command{
+	Use:   "sync-commits",
+	Short: "Add unreleased changes from Conventional Commits",
+	Long: `Adds an entry to the unreleased changes for every Conventional Commit since
+the latest release, read from the local git repository.
+
+Features are added to the Added section, fixes to the Fixed section and
+performance improvements to the Changed section. Breaking changes are marked as
+such. Breaking commits of other types, such as "refactor!:", are added to the
+Changed section, while other commits of those types are skipped. Commits that
+are already part of the unreleased changes are skipped, even when their entry
+was marked as breaking, attributed or given references since.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateChangelog(func(currentChangelog *changelog.Changelog) error {
+			since := ""
+			if len(currentChangelog.Releases) > 0 {
+				since = currentChangelog.Tag(currentChangelog.LatestRelease.Name)
+			}
+
+			commits, err := gitLog(since)
+			if err != nil {
+				return err
+			}
+
+			for _, commit := range commits {
+				section, entry, ok := syncCommit(&currentChangelog.Unreleased, commit)
+				if ok {
+					cmd.Printf("Added \"%s\" to the %s section of the unreleased changes.\n", entry.Description, section)
+				}
+			}
+
+			return nil
+		})
+	},
+}
+
+// syncCommit adds the change of the given commit to the release, unless it is
+// not a Conventional Commit of a type that is added or the release already
+// contains it. An entry contains the change when their summaries are the same
+// regardless of case, so that a change is not added again once its entry has
+// been marked as breaking, attributed or given references.
+func syncCommit(release *changelog.Release, commit commit) (string, changelog.Entry, bool) {
+	section, entry, ok := parseConventionalCommit(commit.Message)
+	if !ok {
+		return "", entry, false
+	}
+
+	summary := entry.Summary(BreakingMarker)
+	for _, existing := range release.Sections {
+		for _, existingEntry := range existing.Entries {
+			if strings.EqualFold(existingEntry.Summary(BreakingMarker), summary) {
+				return "", entry, false
+			}
+		}
+	}
+
+	release.AddEntry(section, entry)
+
+	return section, entry, true
+}
+
+// parseConventionalCommit returns the section and entry of the change described
+// by the given commit message, or false if it is not a Conventional Commit of a
+// type that is added.
+func parseConventionalCommit(message string) (string, changelog.Entry, bool) {
+	lines := strings.Split(message, "\n")
+	match := conventionalCommitRegex.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if match == nil {
+		return "", changelog.Entry{}, false
+	}
+
+	breaking := match[2] == "!"
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			breaking = true
+		}
+	}
+
+	section, ok := commitSections[strings.ToLower(match[1])]
+	if !ok {
+		if !breaking {
+			return "", changelog.Entry{}, false
+		}
+		section = changelog.Changed
+	}
+
+	description := strings.TrimSpace(match[3])
+	first, size := utf8.DecodeRuneInString(description)
+	entry := changelog.Entry{Description: string(unicode.ToUpper(first)) + description[size:]}
+	if breaking {
+		entry.MarkBreaking(BreakingMarker)
+	}
+
+	return section, entry, true
+}
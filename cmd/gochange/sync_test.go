@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+func TestParseConventionalCommit_WhenTypeIsAdded_ReturnsEntry(t *testing.T) {
+	testCases := []struct {
+		message             string
+		expectedSection     string
+		expectedDescription string
+	}{
+		{"feat: add invoices", changelog.Added, "Add invoices"},
+		{"fix(parser): null pointer on empty input", changelog.Fixed, "Null pointer on empty input"},
+		{"perf: cache parsed changelogs", changelog.Changed, "Cache parsed changelogs"},
+		{"feat!: drop support for CHANGES.txt", changelog.Added, "**BREAKING** Drop support for CHANGES.txt"},
+		{"refactor(api)!: rename Parse to Read", changelog.Changed, "**BREAKING** Rename Parse to Read"},
+		{"fix: reject invalid dates\n\nBREAKING CHANGE: dates must be ISO 8601.", changelog.Fixed, "**BREAKING** Reject invalid dates"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.message, func(t *testing.T) {
+			// act
+			section, entry, ok := parseConventionalCommit(testCase.message)
+
+			// assert
+			if !ok {
+				t.Fatalf("expected commit to be added, but it wasn't")
+			}
+			if section != testCase.expectedSection {
+				t.Errorf("expected section to be '%s', but was '%s'", testCase.expectedSection, section)
+			}
+			if entry.Description != testCase.expectedDescription {
+				t.Errorf("expected description to be '%s', but was '%s'", testCase.expectedDescription, entry.Description)
+			}
+		})
+	}
+}
+
+func TestParseConventionalCommit_WhenTypeIsNotAdded_ReturnsFalse(t *testing.T) {
+	testCases := []string{"docs: explain the merge driver", "chore(deps): bump cobra", "Merge pull request #12 from feature", "Fix the build"}
+
+	for _, testCase := range testCases {
+		t.Run(testCase, func(t *testing.T) {
+			// act
+			_, _, ok := parseConventionalCommit(testCase)
+
+			// assert
+			if ok {
+				t.Errorf("expected commit not to be added, but it was")
+			}
+		})
+	}
+}
+
+func TestSyncCommit_WhenEntryExists_SkipsCommit(t *testing.T) {
+	// arrange
+	release := changelog.Release{Sections: []changelog.Section{
+		{Name: changelog.Added, Entries: []changelog.Entry{{Description: "Add invoices"}}},
+	}}
+
+	// act
+	_, _, added := syncCommit(&release, commit{Message: "feat: add invoices"})
+	_, _, fixed := syncCommit(&release, commit{Message: "fix: round totals"})
+
+	// assert
+	if added {
+		t.Errorf("expected existing entry to be skipped, but it was added")
+	}
+	if !fixed {
+		t.Errorf("expected new entry to be added, but it was skipped")
+	}
+	if len(release.Added()) != 1 || len(release.Fixed()) != 1 {
+		t.Errorf("expected one added and one fixed entry, but was %v", release.Sections)
+	}
+}
+
+func TestSyncCommit_WhenEntryWasEdited_SkipsCommit(t *testing.T) {
+	// arrange
+	release := changelog.Release{Sections: []changelog.Section{
+		{Name: changelog.Fixed, Entries: []changelog.Entry{{Description: "**BREAKING** Round totals by @octocat ([#12](https://github.com/mrombout/gochange/issues/12))."}}},
+	}}
+
+	// act
+	_, _, added := syncCommit(&release, commit{Message: "fix!: round totals"})
+
+	// assert
+	if added {
+		t.Errorf("expected existing entry to be skipped, but it was added")
+	}
+	if len(release.Fixed()) != 1 {
+		t.Errorf("expected one fixed entry, but was %v", release.Fixed())
+	}
+}
+
+// git runs git in the working directory and fails the test on errors.
+func git(t *testing.T, args ...string) {
+	t.Helper()
+
+	args = append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+func TestGitLog_WhenSinceTag_ReturnsCommitsAfterTag(t *testing.T) {
+	// arrange
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	chdir(t, t.TempDir())
+	git(t, "init", "--quiet")
+	git(t, "commit", "--quiet", "--allow-empty", "-m", "feat: first")
+	git(t, "tag", "v1.0.0")
+	git(t, "commit", "--quiet", "--allow-empty", "-m", "fix: second")
+	git(t, "commit", "--quiet", "--allow-empty", "-m", "feat: third\n\nWith a body.")
+
+	// act
+	commits, err := gitLog("v1.0.0")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits, but was %d", len(commits))
+	}
+	if commits[0].Message != "fix: second" || commits[1].Message != "feat: third\n\nWith a body." {
+		t.Errorf("expected commits oldest first, but was %v", commits)
+	}
+}
+
+func TestGitLog_WhenTagDoesNotExist_ReturnsError(t *testing.T) {
+	// arrange
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	chdir(t, t.TempDir())
+	git(t, "init", "--quiet")
+	git(t, "commit", "--quiet", "--allow-empty", "-m", "feat: first")
+
+	// act
+	_, err := gitLog("v1.0.0")
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
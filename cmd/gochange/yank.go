@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mrombout/gochange/changelog"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(yankCmd)
+}
+
+var yankCmd = &cobra.Command{
+	Use:   "yank <version>",
+	Short: "Mark a release as yanked",
+	Long:  "Marks an existing release as yanked, because it was pulled due to a serious bug or security issue.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateChangelog(func(currentChangelog *changelog.Changelog) error {
+			return yankRelease(currentChangelog, args[0])
+		})
+	},
+}
+
+// yankRelease marks the release with the given name as yanked.
+func yankRelease(currentChangelog *changelog.Changelog, name string) error {
+	for i := range currentChangelog.Releases {
+		if currentChangelog.Releases[i].Name == name {
+			currentChangelog.Releases[i].Yanked = true
+			if currentChangelog.LatestRelease.Name == name {
+				currentChangelog.LatestRelease.Yanked = true
+			}
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("release %s does not exist", name)
+}
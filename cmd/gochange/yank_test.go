@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mrombout/gochange/changelog"
+)
+
+func TestYankRelease_WhenReleaseExists_MarksReleaseAsYanked(t *testing.T) {
+	// arrange
+	currentChangelog := changelog.Changelog{
+		Releases: []changelog.Release{{Name: "1.0.0"}, {Name: "0.0.5"}},
+	}
+
+	// act
+	err := yankRelease(&currentChangelog, "0.0.5")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected error to be nil, but was '%v'", err)
+	}
+	if currentChangelog.Releases[0].Yanked {
+		t.Errorf("expected release 1.0.0 not to be yanked, but it was")
+	}
+	if !currentChangelog.Releases[1].Yanked {
+		t.Errorf("expected release 0.0.5 to be yanked, but it wasn't")
+	}
+}
+
+func TestYankRelease_WhenReleaseDoesNotExist_ReturnsError(t *testing.T) {
+	// arrange
+	currentChangelog := changelog.Changelog{
+		Releases: []changelog.Release{{Name: "1.0.0"}},
+	}
+
+	// act
+	err := yankRelease(&currentChangelog, "0.0.5")
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}
+
+func TestYank_WhenGivenSeveralVersions_ReturnsError(t *testing.T) {
+	// act
+	err := yankCmd.Args(yankCmd, []string{"1.0.0", "1.1.0"})
+
+	// assert
+	if err == nil {
+		t.Errorf("expected an error, but was nil")
+	}
+}